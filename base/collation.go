@@ -0,0 +1,27 @@
+package base
+
+import (
+	"sync"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// CollationComparator returns a Comparator compares string (or String) values
+// by the collation rules of the language tag, e.g. language.German sorts "ä"
+// as "a" while language.Swedish sorts it after "z". The options are passed to
+// collate.New, e.g. collate.IgnoreCase. Use Key to order plain strings by
+// locale in the containers work on Comparable.
+func CollationComparator(tag language.Tag, options ...collate.Option) Comparator {
+	collator := collate.New(tag, options...)
+	// a collator keeps buffers, it is not safe for concurrent usage
+	var mu sync.Mutex
+
+	return func(a, b interface{}) int {
+		x, y := stringOf(a), stringOf(b)
+
+		mu.Lock()
+		defer mu.Unlock()
+		return collator.CompareString(x, y)
+	}
+}
@@ -0,0 +1,288 @@
+package base
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Comparator describe a comparison function as an alternative to Comparable,
+// it returns a negative integer, zero, or a positive integer as a is less
+// than, equal to, or greater than b. Comparators make it possible to order
+// the same values in different ways and to use plain values as keys.
+type Comparator func(a, b interface{}) int
+
+func sign(result int) int {
+	if result > 0 {
+		return 1
+	} else if result < 0 {
+		return -1
+	}
+	return 0
+}
+
+// NaturalComparator compares Comparable values by CompareTo, and the builtin
+// integer, float, string, bool and time.Time values by their natural order.
+// It panics if a and b are of different types, or of a type without natural order.
+func NaturalComparator(a, b interface{}) int {
+	if c, ok := a.(Comparable); ok {
+		other, ok := b.(Comparable)
+		if !ok {
+			panic(fmt.Sprintf("natural comparator: incomparable types %T and %T", a, b))
+		}
+		return sign(c.CompareTo(other))
+	}
+
+	switch x := a.(type) {
+	case int:
+		return compareInt64(int64(x), int64(b.(int)))
+	case int8:
+		return compareInt64(int64(x), int64(b.(int8)))
+	case int16:
+		return compareInt64(int64(x), int64(b.(int16)))
+	case int32:
+		return compareInt64(int64(x), int64(b.(int32)))
+	case int64:
+		return compareInt64(x, b.(int64))
+	case uint:
+		return compareUint64(uint64(x), uint64(b.(uint)))
+	case uint8:
+		return compareUint64(uint64(x), uint64(b.(uint8)))
+	case uint16:
+		return compareUint64(uint64(x), uint64(b.(uint16)))
+	case uint32:
+		return compareUint64(uint64(x), uint64(b.(uint32)))
+	case uint64:
+		return compareUint64(x, b.(uint64))
+	case uintptr:
+		return compareUint64(uint64(x), uint64(b.(uintptr)))
+	case float32:
+		return Float64(x).CompareTo(Float64(b.(float32)))
+	case float64:
+		return Float64(x).CompareTo(Float64(b.(float64)))
+	case string:
+		return strings.Compare(x, b.(string))
+	case bool:
+		y := b.(bool)
+		if x == y {
+			return 0
+		} else if y {
+			return -1
+		}
+		return 1
+	case time.Time:
+		return Time{x}.CompareTo(Time{b.(time.Time)})
+	}
+
+	panic(fmt.Sprintf("natural comparator: type %T has no natural order", a))
+}
+
+func compareInt64(x, y int64) int {
+	if x > y {
+		return 1
+	} else if x == y {
+		return 0
+	} else {
+		return -1
+	}
+}
+
+func compareUint64(x, y uint64) int {
+	if x > y {
+		return 1
+	} else if x == y {
+		return 0
+	} else {
+		return -1
+	}
+}
+
+// ReverseComparator return a comparator imposes the reverse order of c.
+func ReverseComparator(c Comparator) Comparator {
+	return func(a, b interface{}) int {
+		return c(b, a)
+	}
+}
+
+// ByComparator return a comparator compares the values extracted from a and b
+// by c, e.g. order records by a field.
+func ByComparator(extract func(v interface{}) interface{}, c Comparator) Comparator {
+	return func(a, b interface{}) int {
+		return c(extract(a), extract(b))
+	}
+}
+
+// ChainComparator return a comparator compares by the comparators in turn,
+// the next comparator is used only if the previous ones consider a and b equal.
+func ChainComparator(comparators ...Comparator) Comparator {
+	return func(a, b interface{}) int {
+		for _, c := range comparators {
+			if result := c(a, b); result != 0 {
+				return result
+			}
+		}
+		return 0
+	}
+}
+
+// ThenBy return a comparator compares by c, then by next if c considers a and b equal.
+func (c Comparator) ThenBy(next Comparator) Comparator {
+	return ChainComparator(c, next)
+}
+
+// Reverse return a comparator imposes the reverse order of c.
+func (c Comparator) Reverse() Comparator {
+	return ReverseComparator(c)
+}
+
+func stringOf(v interface{}) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case String:
+		return string(s)
+	}
+	panic(fmt.Sprintf("string comparator: type %T is not a string", v))
+}
+
+// CaseInsensitiveComparator compares string (or String) values ignoring case
+// by unicode simple case folding.
+func CaseInsensitiveComparator(a, b interface{}) int {
+	x, y := stringOf(a), stringOf(b)
+
+	for x != "" && y != "" {
+		rx, sizeX := utf8.DecodeRuneInString(x)
+		ry, sizeY := utf8.DecodeRuneInString(y)
+		x, y = x[sizeX:], y[sizeY:]
+
+		if rx == ry {
+			continue
+		}
+		if result := compareInt64(int64(foldRune(rx)), int64(foldRune(ry))); result != 0 {
+			return result
+		}
+	}
+	return compareInt64(int64(len(x)), int64(len(y)))
+}
+
+// foldRune return the smallest rune of the case folding orbit of r, so that
+// all cases of a letter map to the same rune.
+func foldRune(r rune) rune {
+	min := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < min {
+			min = f
+		}
+	}
+	return min
+}
+
+// latinBase maps the precomposed letters of Latin-1 Supplement and Latin
+// Extended-A to their base letters.
+var latinBase = map[rune]rune{}
+
+func init() {
+	table := []struct {
+		base    rune
+		letters string
+	}{
+		{'a', "àáâãäåāăą"}, {'A', "ÀÁÂÃÄÅĀĂĄ"},
+		{'c', "çćĉċč"}, {'C', "ÇĆĈĊČ"},
+		{'d', "ď"}, {'D', "Ď"},
+		{'e', "èéêëēĕėęě"}, {'E', "ÈÉÊËĒĔĖĘĚ"},
+		{'g', "ĝğġģ"}, {'G', "ĜĞĠĢ"},
+		{'h', "ĥ"}, {'H', "Ĥ"},
+		{'i', "ìíîïĩīĭįı"}, {'I', "ÌÍÎÏĨĪĬĮİ"},
+		{'j', "ĵ"}, {'J', "Ĵ"},
+		{'k', "ķ"}, {'K', "Ķ"},
+		{'l', "ĺļľ"}, {'L', "ĹĻĽ"},
+		{'n', "ñńņň"}, {'N', "ÑŃŅŇ"},
+		{'o', "òóôõöøōŏő"}, {'O', "ÒÓÔÕÖØŌŎŐ"},
+		{'r', "ŕŗř"}, {'R', "ŔŖŘ"},
+		{'s', "śŝşš"}, {'S', "ŚŜŞŠ"},
+		{'t', "ţť"}, {'T', "ŢŤ"},
+		{'u', "ùúûüũūŭůűų"}, {'U', "ÙÚÛÜŨŪŬŮŰŲ"},
+		{'w', "ŵ"}, {'W', "Ŵ"},
+		{'y', "ýÿŷ"}, {'Y', "ÝŶŸ"},
+		{'z', "źżž"}, {'Z', "ŹŻŽ"},
+	}
+
+	for _, item := range table {
+		for _, letter := range item.letters {
+			latinBase[letter] = item.base
+		}
+	}
+}
+
+func baseLetter(r rune) rune {
+	if b, ok := latinBase[r]; ok {
+		return b
+	}
+	return r
+}
+
+// compareLevel compares x and y rune by rune after mapping runes by key.
+func compareLevel(x, y string, key func(r rune) rune) int {
+	for x != "" && y != "" {
+		rx, sizeX := utf8.DecodeRuneInString(x)
+		ry, sizeY := utf8.DecodeRuneInString(y)
+		x, y = x[sizeX:], y[sizeY:]
+
+		if result := compareInt64(int64(key(rx)), int64(key(ry))); result != 0 {
+			return result
+		}
+	}
+	return compareInt64(int64(len(x)), int64(len(y)))
+}
+
+// LatinCollationComparator compares string (or String) values in dictionary
+// order by three levels like the unicode collation algorithm: base letters
+// first, then accents, then case, so that "rest" < "resume" < "Resume" < "résumé".
+// Accents are recognized only for the Latin-1 Supplement and Latin Extended-A
+// letters, other runes are compared by code point. It is not locale-aware,
+// e.g. "ä" sorts as "a" as in German dictionaries but not as in Swedish, use
+// CollationComparator for the rules of a language.
+func LatinCollationComparator(a, b interface{}) int {
+	x, y := stringOf(a), stringOf(b)
+
+	// primary level: base letters ignoring case
+	if result := compareLevel(x, y, func(r rune) rune { return foldRune(baseLetter(r)) }); result != 0 {
+		return result
+	}
+	// secondary level: accents
+	if result := compareLevel(x, y, func(r rune) rune { return foldRune(r) }); result != 0 {
+		return result
+	}
+	// tertiary level: case, lower case first
+	return compareLevel(x, y, func(r rune) rune {
+		if unicode.IsUpper(r) {
+			return 1
+		}
+		return 0
+	})
+}
+
+// ComparatorKey binds a plain value with a comparator as a Comparable, so that
+// plain values are able to be used by the containers work on Comparable.
+type ComparatorKey struct {
+	Value      interface{}
+	Comparator Comparator
+}
+
+// Key return the value as a Comparable ordered by c.
+func (c Comparator) Key(v interface{}) *ComparatorKey {
+	return &ComparatorKey{Value: v, Comparator: c}
+}
+
+func (k *ComparatorKey) CompareTo(o Comparable) int {
+	if other, ok := o.(*ComparatorKey); ok {
+		return k.Comparator(k.Value, other.Value)
+	}
+	return k.Comparator(k.Value, o)
+}
+
+func (k *ComparatorKey) String() string {
+	return fmt.Sprintf("%v", k.Value)
+}
@@ -0,0 +1,150 @@
+package base
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+func TestNaturalComparator(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		a, b     interface{}
+		expected int
+	}{
+		{1, 2, -1},
+		{int8(3), int8(3), 0},
+		{int64(math.MaxInt64), int64(math.MinInt64), 1},
+		{uint64(math.MaxUint64), uint64(0), 1},
+		{1.5, 0.5, 1},
+		{math.NaN(), 0.5, -1},
+		{float32(1), float32(2), -1},
+		{"a", "b", -1},
+		{true, false, 1},
+		{now, now.Add(time.Second), -1},
+		{Int(5), Int(3), 1},
+		{String("x"), String("x"), 0},
+	}
+
+	for _, test := range tests {
+		if result := NaturalComparator(test.a, test.b); result != test.expected {
+			t.Errorf("Got %v expected %v for compare %v and %v", result, test.expected, test.a, test.b)
+		}
+	}
+}
+
+func TestNaturalComparator_panic(t *testing.T) {
+	for _, pair := range [][2]interface{}{{1, "a"}, {Int(1), 1}, {struct{}{}, struct{}{}}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("compare %T and %T not panic", pair[0], pair[1])
+				}
+			}()
+			NaturalComparator(pair[0], pair[1])
+		}()
+	}
+}
+
+type record struct {
+	name string
+	age  int
+}
+
+func TestChainComparator(t *testing.T) {
+	byName := ByComparator(func(v interface{}) interface{} { return v.(record).name }, NaturalComparator)
+	byAge := ByComparator(func(v interface{}) interface{} { return v.(record).age }, NaturalComparator)
+
+	records := []record{{"bob", 30}, {"alice", 30}, {"carol", 25}, {"alice", 20}}
+
+	c := ChainComparator(byAge.Reverse(), byName)
+	sort.Slice(records, func(i, j int) bool { return c(records[i], records[j]) < 0 })
+
+	expected := []record{{"alice", 30}, {"bob", 30}, {"carol", 25}, {"alice", 20}}
+	for i := range records {
+		if records[i] != expected[i] {
+			t.Errorf("Got %v expected %v for chained order", records, expected)
+			break
+		}
+	}
+
+	if byName.ThenBy(byAge)(record{"a", 1}, record{"a", 2}) != -1 {
+		t.Error("ThenBy work error")
+	}
+	if ReverseComparator(NaturalComparator)(1, 2) != 1 {
+		t.Error("ReverseComparator work error")
+	}
+}
+
+func TestCaseInsensitiveComparator(t *testing.T) {
+	tests := []struct {
+		a, b     interface{}
+		expected int
+	}{
+		{"Hello", "hELLO", 0},
+		{"apple", "Banana", -1},
+		{"straße", "STRASSE", 1},
+		{"ΣΊΣΥΦΟΣ", "σίσυφος", 0},
+		{String("abc"), "ABCD", -1},
+	}
+
+	for _, test := range tests {
+		if result := CaseInsensitiveComparator(test.a, test.b); result != test.expected {
+			t.Errorf("Got %v expected %v for compare %v and %v", result, test.expected, test.a, test.b)
+		}
+	}
+}
+
+func TestLatinCollationComparator(t *testing.T) {
+	words := []string{"résumé", "Resume", "rest", "resume", "Äpfel", "apple", "Zebra", "zoo", "élan", "eland"}
+	sort.Slice(words, func(i, j int) bool { return LatinCollationComparator(words[i], words[j]) < 0 })
+
+	expected := []string{"Äpfel", "apple", "élan", "eland", "rest", "resume", "Resume", "résumé", "Zebra", "zoo"}
+	for i := range words {
+		if words[i] != expected[i] {
+			t.Errorf("Got %v expected %v for collation order", words, expected)
+			break
+		}
+	}
+}
+
+func TestCollationComparator(t *testing.T) {
+	words := []string{"zebra", "äpple", "apple"}
+
+	german := CollationComparator(language.German)
+	sort.Slice(words, func(i, j int) bool { return german(words[i], words[j]) < 0 })
+	if expected := []string{"apple", "äpple", "zebra"}; !reflect.DeepEqual(words, expected) {
+		t.Errorf("Got %v expected %v for German collation", words, expected)
+	}
+
+	swedish := CollationComparator(language.Swedish)
+	sort.Slice(words, func(i, j int) bool { return swedish(words[i], words[j]) < 0 })
+	if expected := []string{"apple", "zebra", "äpple"}; !reflect.DeepEqual(words, expected) {
+		t.Errorf("Got %v expected %v for Swedish collation", words, expected)
+	}
+
+	ignoreCase := CollationComparator(language.English, collate.IgnoreCase)
+	if ignoreCase.Key(String("Apple")).CompareTo(ignoreCase.Key("apple")) != 0 {
+		t.Error("CollationComparator with IgnoreCase not ignore case")
+	}
+}
+
+func TestComparatorKey(t *testing.T) {
+	c := Comparator(CaseInsensitiveComparator)
+
+	if c.Key("abc").CompareTo(c.Key("ABC")) != 0 {
+		t.Error("ComparatorKey compare equal values not return 0")
+	}
+	if c.Key("abc").CompareTo(c.Key("abd")) != -1 {
+		t.Error("ComparatorKey compare less value not return -1")
+	}
+	if c.Key(42).String() != "42" {
+		t.Error("ComparatorKey String error")
+	}
+}
@@ -0,0 +1,134 @@
+package base
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ErrIncomparable is matched by errors.Is for every IncomparableError.
+var ErrIncomparable = errors.New("incomparable types")
+
+// IncomparableError describe a comparison between values of different types,
+// or tuples of different orders.
+type IncomparableError struct {
+	Left  Comparable
+	Right Comparable
+}
+
+func (e *IncomparableError) Error() string {
+	return fmt.Sprintf("incomparable types %T and %T", e.Left, e.Right)
+}
+
+// Is makes errors.Is(err, ErrIncomparable) true.
+func (e *IncomparableError) Is(target error) bool {
+	return target == ErrIncomparable
+}
+
+// Compare compares a and b in strict mode, an IncomparableError is returned
+// instead of a silent fallback result if a and b are of different types or
+// nil, or tuples of different orders.
+func Compare(a, b Comparable) (int, error) {
+	if a == nil || b == nil || reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return 0, &IncomparableError{Left: a, Right: b}
+	}
+	if tuple, ok := a.(*Tuple); ok && !tuple.sameOrders(b.(*Tuple)) {
+		return 0, &IncomparableError{Left: a, Right: b}
+	}
+	return a.CompareTo(b), nil
+}
+
+var (
+	typeRanksMu sync.RWMutex
+	typeRanks   = map[reflect.Type]int{
+		reflect.TypeOf(Int(0)):        10,
+		reflect.TypeOf(Int64(0)):      20,
+		reflect.TypeOf(Uint64(0)):     30,
+		reflect.TypeOf(BigInt{}):      40,
+		reflect.TypeOf(Float64(0)):    50,
+		reflect.TypeOf(Duration(0)):   60,
+		reflect.TypeOf(Time{}):        70,
+		reflect.TypeOf(Rune(0)):       80,
+		reflect.TypeOf(String("")):    90,
+		reflect.TypeOf(Bytes(nil)):    100,
+		reflect.TypeOf((*Tuple)(nil)): 110,
+	}
+)
+
+// RegisterTypeRank set the rank of the type of sample used by CompareByTypeRank.
+func RegisterTypeRank(sample Comparable, rank int) {
+	typeRanksMu.Lock()
+	defer typeRanksMu.Unlock()
+	typeRanks[reflect.TypeOf(sample)] = rank
+}
+
+func typeRank(t reflect.Type) (rank int, registered bool) {
+	typeRanksMu.RLock()
+	defer typeRanksMu.RUnlock()
+	rank, registered = typeRanks[t]
+	return
+}
+
+// CompareByTypeRank defines a total order across types: values of the same
+// type are compared by CompareTo, otherwise by the rank of their types.
+// nil is less than any value, unregistered types are greater than registered
+// types and ordered by type name.
+func CompareByTypeRank(a, b Comparable) int {
+	if a == nil || b == nil {
+		if a == nil && b == nil {
+			return 0
+		} else if a == nil {
+			return -1
+		}
+		return 1
+	}
+
+	aType, bType := reflect.TypeOf(a), reflect.TypeOf(b)
+	if aType == bType {
+		return a.CompareTo(b)
+	}
+
+	aRank, aRegistered := typeRank(aType)
+	bRank, bRegistered := typeRank(bType)
+
+	switch {
+	case aRegistered && !bRegistered:
+		return -1
+	case !aRegistered && bRegistered:
+		return 1
+	case aRegistered && bRegistered && aRank != bRank:
+		if aRank > bRank {
+			return 1
+		}
+		return -1
+	}
+
+	if aType.String() > bType.String() {
+		return 1
+	} else if aType.String() == bType.String() {
+		return 0
+	} else {
+		return -1
+	}
+}
+
+// Ranked wraps a Comparable so that values of different types are ordered by
+// CompareByTypeRank, which makes heterogeneous keys safe to be mixed in one container.
+type Ranked struct {
+	Comparable
+}
+
+func (r Ranked) CompareTo(o Comparable) int {
+	if other, ok := o.(Ranked); ok {
+		o = other.Comparable
+	}
+	return CompareByTypeRank(r.Comparable, o)
+}
+
+func (r Ranked) String() string {
+	if r.Comparable == nil {
+		return "<nil>"
+	}
+	return r.Comparable.String()
+}
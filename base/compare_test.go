@@ -0,0 +1,84 @@
+package base
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	if result, err := Compare(Int(1), Int(2)); err != nil || result != -1 {
+		t.Errorf("Got %v, %v expected %v, nil", result, err, -1)
+	}
+
+	_, err := Compare(Int(1), Rune('a'))
+	if !errors.Is(err, ErrIncomparable) {
+		t.Errorf("Got %v expected %v", err, ErrIncomparable)
+	}
+
+	var incomparable *IncomparableError
+	if !errors.As(err, &incomparable) || incomparable.Left != Int(1) || incomparable.Right != Rune('a') {
+		t.Errorf("Got %v expected an IncomparableError", err)
+	}
+	if err.Error() != "incomparable types base.Int and base.Rune" {
+		t.Errorf("Got %q for error message", err.Error())
+	}
+
+	if _, err := Compare(nil, Int(1)); !errors.Is(err, ErrIncomparable) {
+		t.Errorf("Got %v expected %v for nil", err, ErrIncomparable)
+	}
+}
+
+type testKey int
+
+func (k testKey) CompareTo(o Comparable) int { return 0 }
+func (k testKey) String() string             { return "" }
+
+func TestCompareByTypeRank(t *testing.T) {
+	tests := []struct {
+		a, b     Comparable
+		expected int
+	}{
+		{Int(2), Int(1), 1},
+		{Int(100), Rune('a'), -1},
+		{String("a"), Int(100), 1},
+		{nil, Int(1), -1},
+		{nil, nil, 0},
+		{testKey(0), String("z"), 1},
+		{Ranked{Int(1)}, Ranked{Rune('a')}, -1},
+		{Ranked{Rune('a')}, Int(1), 1},
+	}
+
+	for _, test := range tests {
+		if result := CompareByTypeRank(test.a, test.b); result != test.expected {
+			t.Errorf("Got %v expected %v for compare %T and %T", result, test.expected, test.a, test.b)
+		}
+	}
+
+	RegisterTypeRank(testKey(0), 0)
+	defer func() {
+		typeRanksMu.Lock()
+		delete(typeRanks, reflect.TypeOf(testKey(0)))
+		typeRanksMu.Unlock()
+	}()
+	if CompareByTypeRank(testKey(0), Int(1)) != -1 {
+		t.Error("registered type rank not work")
+	}
+}
+
+func TestRanked_CompareTo(t *testing.T) {
+	keys := []Comparable{Ranked{String("a")}, Ranked{Int(3)}, Ranked{Rune('b')}, Ranked{Int(1)}}
+	// insertion sort with CompareTo only
+	for i := 1; i < len(keys); i++ {
+		for j := i; j > 0 && keys[j-1].CompareTo(keys[j]) > 0; j-- {
+			keys[j-1], keys[j] = keys[j], keys[j-1]
+		}
+	}
+
+	expected := []string{"1", "3", "b", "a"}
+	for i, key := range keys {
+		if key.String() != expected[i] {
+			t.Errorf("Got %v expected %v for ranked order", key, expected[i])
+		}
+	}
+}
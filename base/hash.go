@@ -0,0 +1,189 @@
+package base
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// Hashable describe the equality and hashing contract of hash based containers.
+// If a.EqualTo(b) is true, a.Hash() must be equal to b.Hash().
+type Hashable interface {
+	// Hash return the hash code of the object.
+	Hash() uint64
+	// EqualTo return true if the object equals to the specified object,
+	// objects of different types are not equal. It is named after CompareTo
+	// so that it does not hide the Equal methods of embedded types, such as
+	// time.Time.Equal of Time.
+	EqualTo(o Hashable) bool
+}
+
+// Mix64 scrambles the bits of x so that close values get distant hash codes
+// (splitmix64 finalizer).
+func Mix64(x uint64) uint64 {
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// HashBytes return the hash code of b.
+func HashBytes(b []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(b)
+	return Mix64(h.Sum64())
+}
+
+// HashString return the hash code of s.
+func HashString(s string) uint64 {
+	return HashBytes([]byte(s))
+}
+
+// HashCombine combine the hash code of a field into the hash code of a composite value.
+func HashCombine(seed, hash uint64) uint64 {
+	return Mix64(seed ^ (hash + 0x9e3779b97f4a7c15 + (seed << 6) + (seed >> 2)))
+}
+
+func (i Int) Hash() uint64 {
+	return Mix64(uint64(i))
+}
+
+func (i Int) EqualTo(o Hashable) bool {
+	other, ok := o.(Int)
+	return ok && i == other
+}
+
+func (r Rune) Hash() uint64 {
+	return Mix64(uint64(r))
+}
+
+func (r Rune) EqualTo(o Hashable) bool {
+	other, ok := o.(Rune)
+	return ok && r == other
+}
+
+func (s String) Hash() uint64 {
+	return HashString(string(s))
+}
+
+func (s String) EqualTo(o Hashable) bool {
+	other, ok := o.(String)
+	return ok && s == other
+}
+
+// Hash return the hash code of f, all NaNs share a hash code, so do -0 and +0.
+func (f Float64) Hash() uint64 {
+	if math.IsNaN(float64(f)) {
+		return Mix64(0x7ff8000000000001)
+	}
+	if f == 0 {
+		return Mix64(0)
+	}
+	return Mix64(math.Float64bits(float64(f)))
+}
+
+// EqualTo is consistent with CompareTo, NaN equals to NaN and -0 equals to +0.
+func (f Float64) EqualTo(o Hashable) bool {
+	other, ok := o.(Float64)
+	return ok && f.CompareTo(other) == 0
+}
+
+func (i Int64) Hash() uint64 {
+	return Mix64(uint64(i))
+}
+
+func (i Int64) EqualTo(o Hashable) bool {
+	other, ok := o.(Int64)
+	return ok && i == other
+}
+
+func (u Uint64) Hash() uint64 {
+	return Mix64(uint64(u))
+}
+
+func (u Uint64) EqualTo(o Hashable) bool {
+	other, ok := o.(Uint64)
+	return ok && u == other
+}
+
+func (b Bytes) Hash() uint64 {
+	return HashBytes(b)
+}
+
+func (b Bytes) EqualTo(o Hashable) bool {
+	other, ok := o.(Bytes)
+	return ok && b.CompareTo(other) == 0
+}
+
+// Hash return the hash code of the instant, the location is ignored.
+func (t Time) Hash() uint64 {
+	return HashCombine(Mix64(uint64(t.Unix())), uint64(t.Nanosecond()))
+}
+
+func (t Time) EqualTo(o Hashable) bool {
+	other, ok := o.(Time)
+	return ok && t.Equal(other.Time)
+}
+
+func (d Duration) Hash() uint64 {
+	return Mix64(uint64(d))
+}
+
+func (d Duration) EqualTo(o Hashable) bool {
+	other, ok := o.(Duration)
+	return ok && d == other
+}
+
+func (b BigInt) Hash() uint64 {
+	value := b.value()
+	return HashCombine(HashBytes(value.Bytes()), uint64(value.Sign()+1))
+}
+
+func (b BigInt) EqualTo(o Hashable) bool {
+	other, ok := o.(BigInt)
+	return ok && b.CompareTo(other) == 0
+}
+
+// Hash return the hash code of the tuple, a field not implements Hashable is
+// hashed by its String.
+func (t *Tuple) Hash() uint64 {
+	hash := uint64(len(t.Values))
+	for _, v := range t.Values {
+		var h uint64
+		if hashable, ok := v.(Hashable); ok {
+			h = hashable.Hash()
+		} else if v != nil {
+			h = HashString(v.String())
+		}
+		hash = HashCombine(hash, h)
+	}
+	return hash
+}
+
+// EqualTo return true if all fields are equal, a field not implements Hashable
+// is compared by CompareTo, and must have the same String when CompareTo
+// returns 0 to keep the hashing contract. The orders of fields are ignored.
+func (t *Tuple) EqualTo(o Hashable) bool {
+	other, ok := o.(*Tuple)
+	if !ok || len(t.Values) != len(other.Values) {
+		return false
+	}
+
+	for i, v := range t.Values {
+		w := other.Values[i]
+		if v == nil || w == nil {
+			if v != w {
+				return false
+			}
+			continue
+		}
+
+		if hashable, ok := v.(Hashable); ok {
+			otherHashable, ok := w.(Hashable)
+			if !ok || !hashable.EqualTo(otherHashable) {
+				return false
+			}
+		} else if v.CompareTo(w) != 0 {
+			return false
+		}
+	}
+	return true
+}
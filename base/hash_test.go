@@ -0,0 +1,73 @@
+package base
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestHashable(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	equals := [][2]Hashable{
+		{Int(1), Int(1)},
+		{Rune('a'), Rune('a')},
+		{String("abc"), String("abc")},
+		{Float64(math.NaN()), Float64(math.NaN())},
+		{Float64(math.Copysign(0, -1)), Float64(0)},
+		{Int64(-1), Int64(-1)},
+		{Uint64(1), Uint64(1)},
+		{Bytes(nil), Bytes{}},
+		{Time{now}, Time{now.In(time.FixedZone("UTC+8", 8*3600))}},
+		{Duration(time.Second), Duration(time.Second)},
+		{BigInt{big.NewInt(-7)}, BigInt{big.NewInt(-7)}},
+		{BigInt{}, BigInt{big.NewInt(0)}},
+		{NewTuple(String("a"), nil, Int(1)), NewTupleWithOrders([]TupleOrder{Desc}, String("a"), nil, Int(1))},
+	}
+
+	for _, pair := range equals {
+		if !pair[0].EqualTo(pair[1]) || !pair[1].EqualTo(pair[0]) {
+			t.Errorf("%T %v not equal to %v", pair[0], pair[0], pair[1])
+		}
+		if pair[0].Hash() != pair[1].Hash() {
+			t.Errorf("%T equal values %v and %v have different hash codes", pair[0], pair[0], pair[1])
+		}
+	}
+
+	notEquals := [][2]Hashable{
+		{Int(1), Int(2)},
+		{Int(1), Rune(1)},
+		{Int(1), Int64(1)},
+		{String("a"), String("b")},
+		{Float64(1), Float64(math.NaN())},
+		{Bytes{1}, Bytes{1, 0}},
+		{BigInt{big.NewInt(7)}, BigInt{big.NewInt(-7)}},
+		{NewTuple(String("a")), NewTuple(String("a"), nil)},
+		{NewTuple(String("a"), nil), NewTuple(String("a"), Int(0))},
+	}
+
+	for _, pair := range notEquals {
+		if pair[0].EqualTo(pair[1]) {
+			t.Errorf("%T %v equal to %T %v", pair[0], pair[0], pair[1], pair[1])
+		}
+	}
+
+	// the Equal of time.Time is still promoted
+	if !(Time{now}).Equal(now.In(time.FixedZone("UTC+8", 8*3600))) {
+		t.Error("Time not equal to the same instant in another location")
+	}
+}
+
+func TestHashDistribution(t *testing.T) {
+	// the low bits of hash codes of sequential keys should be well distributed
+	buckets := make([]int, 16)
+	for i := 0; i < 1600; i++ {
+		buckets[Int(i).Hash()&15]++
+	}
+	for i, count := range buckets {
+		if count < 50 || count > 150 {
+			t.Errorf("Bucket %d got %d of 1600 sequential keys", i, count)
+		}
+	}
+}
@@ -1,6 +1,14 @@
 package base
 
-import "fmt"
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+)
 
 type Int int
 
@@ -59,3 +67,241 @@ func NewRuneComparableSlice(slice []rune) (r []Comparable) {
 
 	return
 }
+
+type String string
+
+func (s String) CompareTo(o Comparable) int {
+	other, ok := o.(String)
+	if !ok {
+		return 1
+	}
+
+	if s > other {
+		return 1
+	} else if s == other {
+		return 0
+	} else {
+		return -1
+	}
+}
+
+func (s String) String() string {
+	return string(s)
+}
+
+func NewStringComparableSlice(slice []string) (s []Comparable) {
+	for _, v := range slice {
+		s = append(s, String(v))
+	}
+	return
+}
+
+// Float64 is a float64 with a total order, NaN is less than any other value
+// and equal to NaN, -0 is equal to +0.
+type Float64 float64
+
+func (f Float64) CompareTo(o Comparable) int {
+	other, ok := o.(Float64)
+	if !ok {
+		return 1
+	}
+
+	fNaN, otherNaN := math.IsNaN(float64(f)), math.IsNaN(float64(other))
+	if fNaN || otherNaN {
+		if fNaN && otherNaN {
+			return 0
+		} else if fNaN {
+			return -1
+		}
+		return 1
+	}
+
+	if f > other {
+		return 1
+	} else if f == other {
+		return 0
+	} else {
+		return -1
+	}
+}
+
+func (f Float64) String() string {
+	return strconv.FormatFloat(float64(f), 'g', -1, 64)
+}
+
+func NewFloat64ComparableSlice(slice []float64) (s []Comparable) {
+	for _, v := range slice {
+		s = append(s, Float64(v))
+	}
+	return
+}
+
+type Int64 int64
+
+func (i Int64) CompareTo(o Comparable) int {
+	other, ok := o.(Int64)
+	if !ok {
+		return 1
+	}
+
+	if i > other {
+		return 1
+	} else if i == other {
+		return 0
+	} else {
+		return -1
+	}
+}
+
+func (i Int64) String() string {
+	return strconv.FormatInt(int64(i), 10)
+}
+
+func NewInt64ComparableSlice(slice []int64) (s []Comparable) {
+	for _, v := range slice {
+		s = append(s, Int64(v))
+	}
+	return
+}
+
+type Uint64 uint64
+
+func (u Uint64) CompareTo(o Comparable) int {
+	other, ok := o.(Uint64)
+	if !ok {
+		return 1
+	}
+
+	if u > other {
+		return 1
+	} else if u == other {
+		return 0
+	} else {
+		return -1
+	}
+}
+
+func (u Uint64) String() string {
+	return strconv.FormatUint(uint64(u), 10)
+}
+
+func NewUint64ComparableSlice(slice []uint64) (s []Comparable) {
+	for _, v := range slice {
+		s = append(s, Uint64(v))
+	}
+	return
+}
+
+// Bytes is a byte slice compared lexicographically, nil is equal to empty slice.
+type Bytes []byte
+
+func (b Bytes) CompareTo(o Comparable) int {
+	other, ok := o.(Bytes)
+	if !ok {
+		return 1
+	}
+	return bytes.Compare(b, other)
+}
+
+// String returns the hex encoding of the bytes.
+func (b Bytes) String() string {
+	return hex.EncodeToString(b)
+}
+
+func NewBytesComparableSlice(slice [][]byte) (s []Comparable) {
+	for _, v := range slice {
+		s = append(s, Bytes(v))
+	}
+	return
+}
+
+// Time is a time.Time compared by instant, the location is ignored.
+type Time struct {
+	time.Time
+}
+
+func (t Time) CompareTo(o Comparable) int {
+	other, ok := o.(Time)
+	if !ok {
+		return 1
+	}
+
+	if t.After(other.Time) {
+		return 1
+	} else if t.Equal(other.Time) {
+		return 0
+	} else {
+		return -1
+	}
+}
+
+// String returns the time formatted in RFC3339 with nanoseconds.
+func (t Time) String() string {
+	return t.Format(time.RFC3339Nano)
+}
+
+func NewTimeComparableSlice(slice []time.Time) (s []Comparable) {
+	for _, v := range slice {
+		s = append(s, Time{v})
+	}
+	return
+}
+
+type Duration time.Duration
+
+func (d Duration) CompareTo(o Comparable) int {
+	other, ok := o.(Duration)
+	if !ok {
+		return 1
+	}
+
+	if d > other {
+		return 1
+	} else if d == other {
+		return 0
+	} else {
+		return -1
+	}
+}
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+func NewDurationComparableSlice(slice []time.Duration) (s []Comparable) {
+	for _, v := range slice {
+		s = append(s, Duration(v))
+	}
+	return
+}
+
+// BigInt is a big.Int, a nil Int is treated as zero.
+type BigInt struct {
+	*big.Int
+}
+
+func (b BigInt) value() *big.Int {
+	if b.Int == nil {
+		return new(big.Int)
+	}
+	return b.Int
+}
+
+func (b BigInt) CompareTo(o Comparable) int {
+	other, ok := o.(BigInt)
+	if !ok {
+		return 1
+	}
+	return b.value().Cmp(other.value())
+}
+
+func (b BigInt) String() string {
+	return b.value().String()
+}
+
+func NewBigIntComparableSlice(slice []*big.Int) (s []Comparable) {
+	for _, v := range slice {
+		s = append(s, BigInt{v})
+	}
+	return
+}
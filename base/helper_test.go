@@ -1,7 +1,10 @@
 package base
 
 import (
+	"math"
+	"math/big"
 	"testing"
+	"time"
 )
 
 func TestIntSlice(t *testing.T) {
@@ -72,3 +75,153 @@ func TestRune_CompareTo(t *testing.T) {
 	}
 
 }
+
+// assertCompareTo check less, equal, greater and different type comparison.
+func assertCompareTo(t *testing.T, less, equal, greater Comparable) {
+	if equal.CompareTo(Int(2)) != 1 {
+		t.Errorf("%T compare different type not return 1", equal)
+	}
+	if equal.CompareTo(less) != 1 || less.CompareTo(equal) != -1 {
+		t.Errorf("%T compare %v and %v error", equal, equal, less)
+	}
+	if equal.CompareTo(greater) != -1 || greater.CompareTo(equal) != 1 {
+		t.Errorf("%T compare %v and %v error", equal, equal, greater)
+	}
+	if equal.CompareTo(equal) != 0 {
+		t.Errorf("%T equal compare not return 0", equal)
+	}
+}
+
+func TestString_CompareTo(t *testing.T) {
+	assertCompareTo(t, String("abc"), String("abd"), String("b"))
+	assertCompareTo(t, String(""), String("a"), String("aa"))
+
+	if String("abc").String() != "abc" {
+		t.Error("String String error")
+	}
+
+	order := NewStringComparableSlice([]string{"a", "b"})
+	if len(order) != 2 || order[1].CompareTo(String("b")) != 0 {
+		t.Error("StringSlice work error")
+	}
+}
+
+func TestFloat64_CompareTo(t *testing.T) {
+	nan := Float64(math.NaN())
+
+	assertCompareTo(t, Float64(-1.5), Float64(0), Float64(1e-9))
+	assertCompareTo(t, Float64(math.Inf(-1)), Float64(-1e308), Float64(math.Inf(1)))
+	assertCompareTo(t, nan, Float64(math.Inf(-1)), Float64(0))
+
+	if nan.CompareTo(Float64(math.NaN())) != 0 {
+		t.Error("NaN compare to NaN not return 0")
+	}
+	if Float64(math.Copysign(0, -1)).CompareTo(Float64(0)) != 0 {
+		t.Error("-0 compare to +0 not return 0")
+	}
+
+	if Float64(1.5).String() != "1.5" || nan.String() != "NaN" {
+		t.Error("Float64 String error")
+	}
+
+	order := NewFloat64ComparableSlice([]float64{1.5, 2})
+	if len(order) != 2 || order[0].CompareTo(Float64(1.5)) != 0 {
+		t.Error("Float64Slice work error")
+	}
+}
+
+func TestInt64_CompareTo(t *testing.T) {
+	assertCompareTo(t, Int64(math.MinInt64), Int64(0), Int64(math.MaxInt64))
+
+	if Int64(-42).String() != "-42" {
+		t.Error("Int64 String error")
+	}
+
+	order := NewInt64ComparableSlice([]int64{1, 2})
+	if len(order) != 2 || order[1].CompareTo(Int64(2)) != 0 {
+		t.Error("Int64Slice work error")
+	}
+}
+
+func TestUint64_CompareTo(t *testing.T) {
+	assertCompareTo(t, Uint64(0), Uint64(1<<63), Uint64(math.MaxUint64))
+
+	if Uint64(math.MaxUint64).String() != "18446744073709551615" {
+		t.Error("Uint64 String error")
+	}
+
+	order := NewUint64ComparableSlice([]uint64{1, 2})
+	if len(order) != 2 || order[1].CompareTo(Uint64(2)) != 0 {
+		t.Error("Uint64Slice work error")
+	}
+}
+
+func TestBytes_CompareTo(t *testing.T) {
+	assertCompareTo(t, Bytes{0x01}, Bytes{0x01, 0x00}, Bytes{0x02})
+	assertCompareTo(t, Bytes(nil), Bytes{0x00}, Bytes{0xff})
+
+	if Bytes(nil).CompareTo(Bytes{}) != 0 {
+		t.Error("nil bytes compare to empty bytes not return 0")
+	}
+
+	if (Bytes{0xca, 0xfe}).String() != "cafe" {
+		t.Error("Bytes String error")
+	}
+
+	order := NewBytesComparableSlice([][]byte{{1}, {2}})
+	if len(order) != 2 || order[1].CompareTo(Bytes{2}) != 0 {
+		t.Error("BytesSlice work error")
+	}
+}
+
+func TestTime_CompareTo(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	assertCompareTo(t, Time{now.Add(-time.Nanosecond)}, Time{now}, Time{now.Add(time.Hour)})
+
+	if (Time{now}).CompareTo(Time{now.In(time.FixedZone("UTC+8", 8*3600))}) != 0 {
+		t.Error("same instant in different location compare not return 0")
+	}
+
+	if (Time{now}).String() != "2020-01-02T03:04:05.000000006Z" {
+		t.Error("Time String error")
+	}
+
+	order := NewTimeComparableSlice([]time.Time{now})
+	if len(order) != 1 || order[0].CompareTo(Time{now}) != 0 {
+		t.Error("TimeSlice work error")
+	}
+}
+
+func TestDuration_CompareTo(t *testing.T) {
+	assertCompareTo(t, Duration(-time.Second), Duration(time.Millisecond), Duration(time.Hour))
+
+	if Duration(90*time.Second).String() != "1m30s" {
+		t.Error("Duration String error")
+	}
+
+	order := NewDurationComparableSlice([]time.Duration{time.Second})
+	if len(order) != 1 || order[0].CompareTo(Duration(time.Second)) != 0 {
+		t.Error("DurationSlice work error")
+	}
+}
+
+func TestBigInt_CompareTo(t *testing.T) {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	negative := new(big.Int).Neg(huge)
+
+	assertCompareTo(t, BigInt{negative}, BigInt{big.NewInt(0)}, BigInt{huge})
+
+	if (BigInt{}).CompareTo(BigInt{big.NewInt(0)}) != 0 {
+		t.Error("nil BigInt compare to zero not return 0")
+	}
+
+	if (BigInt{huge}).String() != "123456789012345678901234567890" || (BigInt{}).String() != "0" {
+		t.Error("BigInt String error")
+	}
+
+	order := NewBigIntComparableSlice([]*big.Int{huge})
+	if len(order) != 1 || order[0].CompareTo(BigInt{huge}) != 0 {
+		t.Error("BigIntSlice work error")
+	}
+}
@@ -0,0 +1,150 @@
+package base
+
+import (
+	"bytes"
+)
+
+// TupleOrder describe how a field of tuple is ordered.
+// The zero value is ascending with NULL (nil) first.
+type TupleOrder struct {
+	Descending bool // Order the field in descending order
+	NullsLast  bool // Place NULL after all non-NULL values, regardless of Descending
+}
+
+var (
+	// Asc orders a field ascending with NULL first.
+	Asc = TupleOrder{}
+	// Desc orders a field descending with NULL first.
+	Desc = TupleOrder{Descending: true}
+)
+
+// Tuple is a composite key of ordered fields compared lexicographically, so
+// that multi-column keys are able to be indexed directly, e.g. (tenant, timestamp, id).
+// A nil field is treated as NULL. If all fields of the shorter tuple are equal
+// to the longer one, the shorter tuple is less.
+// Fields are ordered by the Orders, a field without order is ascending with
+// NULL first. Tuples compared with each other must have the same Orders,
+// Compare returns an IncomparableError otherwise, and CompareTo orders them
+// by the first field of different orders so that it is still antisymmetric.
+type Tuple struct {
+	Values []Comparable
+	Orders []TupleOrder
+}
+
+// NewTuple return a tuple with all fields ascending and NULL first.
+func NewTuple(values ...Comparable) *Tuple {
+	return &Tuple{Values: values}
+}
+
+// NewTupleWithOrders return a tuple ordered by the orders, orders[i] is the
+// order of values[i].
+func NewTupleWithOrders(orders []TupleOrder, values ...Comparable) *Tuple {
+	return &Tuple{Values: values, Orders: orders}
+}
+
+func (t *Tuple) order(i int) TupleOrder {
+	if i < len(t.Orders) {
+		return t.Orders[i]
+	}
+	return TupleOrder{}
+}
+
+// rank orders the TupleOrders for tuples of different orders.
+func (o TupleOrder) rank() int {
+	rank := 0
+	if o.Descending {
+		rank += 2
+	}
+	if o.NullsLast {
+		rank++
+	}
+	return rank
+}
+
+// sameOrders checks if every field of t and o is ordered the same way.
+func (t *Tuple) sameOrders(o *Tuple) bool {
+	for i := 0; i < len(t.Values) || i < len(o.Values); i++ {
+		if t.order(i) != o.order(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// Len returns the number of fields.
+func (t *Tuple) Len() int {
+	return len(t.Values)
+}
+
+func (t *Tuple) CompareTo(o Comparable) int {
+	other, ok := o.(*Tuple)
+	if !ok {
+		return 1
+	}
+
+	for i := 0; i < len(t.Values) && i < len(other.Values); i++ {
+		order, otherOrder := t.order(i), other.order(i)
+		if order != otherOrder {
+			if order.rank() > otherOrder.rank() {
+				return 1
+			}
+			return -1
+		}
+		a, b := t.Values[i], other.Values[i]
+
+		var result int
+		if a == nil || b == nil {
+			if a == nil && b == nil {
+				continue
+			} else if a == nil {
+				result = -1
+			} else {
+				result = 1
+			}
+			if order.NullsLast {
+				result = -result
+			}
+			return result
+		}
+
+		result = a.CompareTo(b)
+		if result == 0 {
+			continue
+		}
+		if result > 0 {
+			result = 1
+		} else {
+			result = -1
+		}
+		if order.Descending {
+			result = -result
+		}
+		return result
+	}
+
+	if len(t.Values) > len(other.Values) {
+		return 1
+	} else if len(t.Values) == len(other.Values) {
+		return 0
+	} else {
+		return -1
+	}
+}
+
+// String return the tuple in format (a, b, NULL).
+func (t *Tuple) String() string {
+	buffer := new(bytes.Buffer)
+	buffer.WriteString("(")
+	for i, v := range t.Values {
+		if i > 0 {
+			buffer.WriteString(", ")
+		}
+		if v == nil {
+			buffer.WriteString("NULL")
+		} else {
+			buffer.WriteString(v.String())
+		}
+	}
+	buffer.WriteString(")")
+	return buffer.String()
+}
@@ -0,0 +1,75 @@
+package base
+
+import (
+	"testing"
+)
+
+func TestTuple_CompareTo(t *testing.T) {
+	if NewTuple(Int(1)).CompareTo(Int(1)) != 1 {
+		t.Error("compare different type not return 1")
+	}
+
+	ascDesc := []TupleOrder{Asc, Desc}
+	desc := []TupleOrder{Desc}
+	nullsLast := []TupleOrder{{NullsLast: true}}
+	descNullsLast := []TupleOrder{{Descending: true, NullsLast: true}}
+
+	tests := []struct {
+		a, b     *Tuple
+		expected int
+	}{
+		{NewTuple(String("a"), Int(1)), NewTuple(String("a"), Int(1)), 0},
+		{NewTuple(String("a"), Int(1)), NewTuple(String("a"), Int(2)), -1},
+		{NewTuple(String("b"), Int(1)), NewTuple(String("a"), Int(2)), 1},
+		{NewTuple(String("a")), NewTuple(String("a"), Int(2)), -1},
+		{NewTuple(), NewTuple(), 0},
+		{NewTuple(nil, Int(1)), NewTuple(String("a"), Int(0)), -1},
+		{NewTuple(nil, Int(1)), NewTuple(nil, Int(0)), 1},
+		{NewTupleWithOrders(ascDesc, String("a"), Int(1)), NewTupleWithOrders(ascDesc, String("a"), Int(2)), 1},
+		{NewTupleWithOrders(desc, String("a"), Int(1)), NewTupleWithOrders(desc, String("b"), Int(0)), 1},
+		{NewTupleWithOrders(nullsLast, nil), NewTupleWithOrders(nullsLast, String("a")), 1},
+		{NewTupleWithOrders(descNullsLast, String("z")), NewTupleWithOrders(descNullsLast, nil), -1},
+		{NewTupleWithOrders(desc, nil), NewTupleWithOrders(desc, String("a")), -1},
+	}
+
+	for _, test := range tests {
+		if result := test.a.CompareTo(test.b); result != test.expected {
+			t.Errorf("Got %v expected %v for compare %s and %s", result, test.expected, test.a, test.b)
+		}
+		if result := test.b.CompareTo(test.a); result != -test.expected {
+			t.Errorf("Got %v expected %v for compare %s and %s", result, -test.expected, test.b, test.a)
+		}
+	}
+}
+
+func TestTuple_CompareDifferentOrders(t *testing.T) {
+	asc := NewTuple(String("a"), Int(1))
+	desc := NewTupleWithOrders([]TupleOrder{Asc, Desc}, String("a"), Int(2))
+
+	// antisymmetric even if the orders are different
+	if asc.CompareTo(desc) != -desc.CompareTo(asc) || asc.CompareTo(desc) == 0 {
+		t.Errorf("Got %v and %v for compare tuples of different orders", asc.CompareTo(desc), desc.CompareTo(asc))
+	}
+
+	if _, err := Compare(asc, desc); err == nil {
+		t.Error("Compare tuples of different orders not return IncomparableError")
+	}
+	if result, err := Compare(asc, NewTupleWithOrders([]TupleOrder{Asc, Asc}, String("a"), Int(2))); err != nil || result != -1 {
+		t.Errorf("Got %v, %v expected %v for compare tuples of the same orders", result, err, -1)
+	}
+}
+
+func TestTuple_String(t *testing.T) {
+	tuple := NewTuple(String("tenant"), Int(42), nil)
+	if tuple.String() != "(tenant, 42, NULL)" {
+		t.Errorf("Got %s expected %s for tuple string", tuple, "(tenant, 42, NULL)")
+	}
+
+	if NewTuple().String() != "()" {
+		t.Error("empty tuple string error")
+	}
+
+	if tuple.Len() != 3 {
+		t.Error("tuple length error")
+	}
+}
@@ -0,0 +1,467 @@
+package codec
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/aiden0z/kit/base"
+)
+
+// funcCodec implements KeyCodec by functions.
+type funcCodec struct {
+	appendBinary  func(dst []byte, key base.Comparable) ([]byte, error)
+	decodeBinary  func(data []byte) (base.Comparable, error)
+	encodeJSON    func(key base.Comparable) ([]byte, error)
+	decodeJSON    func(data []byte) (base.Comparable, error)
+	appendOrdered func(dst []byte, key base.Comparable) ([]byte, error)
+	decodeOrdered func(data []byte) (base.Comparable, []byte, error)
+	check         func(key base.Comparable) bool
+}
+
+func (c *funcCodec) checkKey(key base.Comparable) error {
+	if !c.check(key) {
+		return fmt.Errorf("%w: %T", KeyTypeErr, key)
+	}
+	return nil
+}
+
+func (c *funcCodec) AppendBinary(dst []byte, key base.Comparable) ([]byte, error) {
+	if err := c.checkKey(key); err != nil {
+		return nil, err
+	}
+	return c.appendBinary(dst, key)
+}
+
+func (c *funcCodec) DecodeBinary(data []byte) (base.Comparable, error) {
+	return c.decodeBinary(data)
+}
+
+func (c *funcCodec) EncodeJSON(key base.Comparable) ([]byte, error) {
+	if err := c.checkKey(key); err != nil {
+		return nil, err
+	}
+	return c.encodeJSON(key)
+}
+
+func (c *funcCodec) DecodeJSON(data []byte) (base.Comparable, error) {
+	return c.decodeJSON(data)
+}
+
+func (c *funcCodec) AppendOrdered(dst []byte, key base.Comparable) ([]byte, error) {
+	if err := c.checkKey(key); err != nil {
+		return nil, err
+	}
+	return c.appendOrdered(dst, key)
+}
+
+func (c *funcCodec) DecodeOrdered(data []byte) (base.Comparable, []byte, error) {
+	return c.decodeOrdered(data)
+}
+
+var (
+	// IntCodec is the codec of base.Int, registered as "int".
+	IntCodec KeyCodec = &funcCodec{
+		check: func(key base.Comparable) bool { _, ok := key.(base.Int); return ok },
+		appendBinary: func(dst []byte, key base.Comparable) ([]byte, error) {
+			return appendVarint(dst, int64(key.(base.Int))), nil
+		},
+		decodeBinary: func(data []byte) (base.Comparable, error) {
+			x, err := decodeVarint(data)
+			return base.Int(x), err
+		},
+		encodeJSON: func(key base.Comparable) ([]byte, error) {
+			return json.Marshal(int64(key.(base.Int)))
+		},
+		decodeJSON: func(data []byte) (base.Comparable, error) {
+			var x int64
+			err := json.Unmarshal(data, &x)
+			return base.Int(x), err
+		},
+		appendOrdered: func(dst []byte, key base.Comparable) ([]byte, error) {
+			return appendOrderedInt64(dst, int64(key.(base.Int))), nil
+		},
+		decodeOrdered: func(data []byte) (base.Comparable, []byte, error) {
+			x, rest, err := decodeOrderedInt64(data)
+			return base.Int(x), rest, err
+		},
+	}
+
+	// RuneCodec is the codec of base.Rune, registered as "rune".
+	RuneCodec KeyCodec = &funcCodec{
+		check: func(key base.Comparable) bool { _, ok := key.(base.Rune); return ok },
+		appendBinary: func(dst []byte, key base.Comparable) ([]byte, error) {
+			return appendVarint(dst, int64(key.(base.Rune))), nil
+		},
+		decodeBinary: func(data []byte) (base.Comparable, error) {
+			x, err := decodeVarint(data)
+			if x < math.MinInt32 || x > math.MaxInt32 {
+				return nil, InvalidEncodingErr
+			}
+			return base.Rune(x), err
+		},
+		encodeJSON: func(key base.Comparable) ([]byte, error) {
+			return json.Marshal(int32(key.(base.Rune)))
+		},
+		decodeJSON: func(data []byte) (base.Comparable, error) {
+			var x int32
+			err := json.Unmarshal(data, &x)
+			return base.Rune(x), err
+		},
+		appendOrdered: func(dst []byte, key base.Comparable) ([]byte, error) {
+			return appendOrderedInt32(dst, int32(key.(base.Rune))), nil
+		},
+		decodeOrdered: func(data []byte) (base.Comparable, []byte, error) {
+			x, rest, err := decodeOrderedInt32(data)
+			return base.Rune(x), rest, err
+		},
+	}
+
+	// StringCodec is the codec of base.String, registered as "string".
+	// The JSON encoding replaces invalid UTF-8 with U+FFFD, use base.Bytes
+	// for arbitrary bytes.
+	StringCodec KeyCodec = &funcCodec{
+		check: func(key base.Comparable) bool { _, ok := key.(base.String); return ok },
+		appendBinary: func(dst []byte, key base.Comparable) ([]byte, error) {
+			return append(dst, key.(base.String)...), nil
+		},
+		decodeBinary: func(data []byte) (base.Comparable, error) {
+			return base.String(data), nil
+		},
+		encodeJSON: func(key base.Comparable) ([]byte, error) {
+			return json.Marshal(string(key.(base.String)))
+		},
+		decodeJSON: func(data []byte) (base.Comparable, error) {
+			var s string
+			err := json.Unmarshal(data, &s)
+			return base.String(s), err
+		},
+		appendOrdered: func(dst []byte, key base.Comparable) ([]byte, error) {
+			return appendOrderedBytes(dst, []byte(key.(base.String))), nil
+		},
+		decodeOrdered: func(data []byte) (base.Comparable, []byte, error) {
+			b, rest, err := decodeOrderedBytes(data)
+			return base.String(b), rest, err
+		},
+	}
+
+	// Float64Codec is the codec of base.Float64, registered as "float64".
+	// NaN and infinities are encoded as JSON strings "NaN", "+Inf" and "-Inf".
+	Float64Codec KeyCodec = &funcCodec{
+		check: func(key base.Comparable) bool { _, ok := key.(base.Float64); return ok },
+		appendBinary: func(dst []byte, key base.Comparable) ([]byte, error) {
+			return appendOrderedUint64(dst, math.Float64bits(float64(key.(base.Float64)))), nil
+		},
+		decodeBinary: func(data []byte) (base.Comparable, error) {
+			bits, rest, err := decodeOrderedUint64(data)
+			if err != nil || len(rest) != 0 {
+				return nil, InvalidEncodingErr
+			}
+			return base.Float64(math.Float64frombits(bits)), nil
+		},
+		encodeJSON: func(key base.Comparable) ([]byte, error) {
+			f := float64(key.(base.Float64))
+			if math.IsNaN(f) || math.IsInf(f, 0) {
+				return json.Marshal(strconv.FormatFloat(f, 'g', -1, 64))
+			}
+			return json.Marshal(f)
+		},
+		decodeJSON: func(data []byte) (base.Comparable, error) {
+			var s string
+			if json.Unmarshal(data, &s) == nil {
+				f, err := strconv.ParseFloat(s, 64)
+				return base.Float64(f), err
+			}
+			var f float64
+			err := json.Unmarshal(data, &f)
+			return base.Float64(f), err
+		},
+		appendOrdered: func(dst []byte, key base.Comparable) ([]byte, error) {
+			return appendOrderedFloat64(dst, float64(key.(base.Float64))), nil
+		},
+		decodeOrdered: func(data []byte) (base.Comparable, []byte, error) {
+			f, rest, err := decodeOrderedFloat64(data)
+			return base.Float64(f), rest, err
+		},
+	}
+
+	// Int64Codec is the codec of base.Int64, registered as "int64".
+	Int64Codec KeyCodec = &funcCodec{
+		check: func(key base.Comparable) bool { _, ok := key.(base.Int64); return ok },
+		appendBinary: func(dst []byte, key base.Comparable) ([]byte, error) {
+			return appendVarint(dst, int64(key.(base.Int64))), nil
+		},
+		decodeBinary: func(data []byte) (base.Comparable, error) {
+			x, err := decodeVarint(data)
+			return base.Int64(x), err
+		},
+		encodeJSON: func(key base.Comparable) ([]byte, error) {
+			return json.Marshal(int64(key.(base.Int64)))
+		},
+		decodeJSON: func(data []byte) (base.Comparable, error) {
+			var x int64
+			err := json.Unmarshal(data, &x)
+			return base.Int64(x), err
+		},
+		appendOrdered: func(dst []byte, key base.Comparable) ([]byte, error) {
+			return appendOrderedInt64(dst, int64(key.(base.Int64))), nil
+		},
+		decodeOrdered: func(data []byte) (base.Comparable, []byte, error) {
+			x, rest, err := decodeOrderedInt64(data)
+			return base.Int64(x), rest, err
+		},
+	}
+
+	// Uint64Codec is the codec of base.Uint64, registered as "uint64".
+	Uint64Codec KeyCodec = &funcCodec{
+		check: func(key base.Comparable) bool { _, ok := key.(base.Uint64); return ok },
+		appendBinary: func(dst []byte, key base.Comparable) ([]byte, error) {
+			return appendUvarint(dst, uint64(key.(base.Uint64))), nil
+		},
+		decodeBinary: func(data []byte) (base.Comparable, error) {
+			x, err := decodeUvarint(data)
+			return base.Uint64(x), err
+		},
+		encodeJSON: func(key base.Comparable) ([]byte, error) {
+			return json.Marshal(uint64(key.(base.Uint64)))
+		},
+		decodeJSON: func(data []byte) (base.Comparable, error) {
+			var x uint64
+			err := json.Unmarshal(data, &x)
+			return base.Uint64(x), err
+		},
+		appendOrdered: func(dst []byte, key base.Comparable) ([]byte, error) {
+			return appendOrderedUint64(dst, uint64(key.(base.Uint64))), nil
+		},
+		decodeOrdered: func(data []byte) (base.Comparable, []byte, error) {
+			x, rest, err := decodeOrderedUint64(data)
+			return base.Uint64(x), rest, err
+		},
+	}
+
+	// BytesCodec is the codec of base.Bytes, registered as "bytes".
+	// The JSON encoding is a base64 string.
+	BytesCodec KeyCodec = &funcCodec{
+		check: func(key base.Comparable) bool { _, ok := key.(base.Bytes); return ok },
+		appendBinary: func(dst []byte, key base.Comparable) ([]byte, error) {
+			return append(dst, key.(base.Bytes)...), nil
+		},
+		decodeBinary: func(data []byte) (base.Comparable, error) {
+			return base.Bytes(append([]byte{}, data...)), nil
+		},
+		encodeJSON: func(key base.Comparable) ([]byte, error) {
+			return json.Marshal([]byte(key.(base.Bytes)))
+		},
+		decodeJSON: func(data []byte) (base.Comparable, error) {
+			var b []byte
+			err := json.Unmarshal(data, &b)
+			return base.Bytes(b), err
+		},
+		appendOrdered: func(dst []byte, key base.Comparable) ([]byte, error) {
+			return appendOrderedBytes(dst, key.(base.Bytes)), nil
+		},
+		decodeOrdered: func(data []byte) (base.Comparable, []byte, error) {
+			b, rest, err := decodeOrderedBytes(data)
+			return base.Bytes(b), rest, err
+		},
+	}
+
+	// TimeCodec is the codec of base.Time, registered as "time".
+	// The binary and JSON encodings keep the zone offset, the ordered encoding
+	// keeps the instant only and decodes to UTC.
+	TimeCodec KeyCodec = &funcCodec{
+		check: func(key base.Comparable) bool { _, ok := key.(base.Time); return ok },
+		appendBinary: func(dst []byte, key base.Comparable) ([]byte, error) {
+			data, err := key.(base.Time).Time.MarshalBinary()
+			if err != nil {
+				return nil, err
+			}
+			return append(dst, data...), nil
+		},
+		decodeBinary: func(data []byte) (base.Comparable, error) {
+			var t time.Time
+			err := t.UnmarshalBinary(data)
+			return base.Time{Time: t}, err
+		},
+		encodeJSON: func(key base.Comparable) ([]byte, error) {
+			return key.(base.Time).Time.MarshalJSON()
+		},
+		decodeJSON: func(data []byte) (base.Comparable, error) {
+			var t time.Time
+			err := t.UnmarshalJSON(data)
+			return base.Time{Time: t}, err
+		},
+		appendOrdered: func(dst []byte, key base.Comparable) ([]byte, error) {
+			t := key.(base.Time)
+			dst = appendOrderedInt64(dst, t.Unix())
+			return appendOrderedInt32(dst, int32(t.Nanosecond())), nil
+		},
+		decodeOrdered: func(data []byte) (base.Comparable, []byte, error) {
+			sec, rest, err := decodeOrderedInt64(data)
+			if err != nil {
+				return nil, nil, err
+			}
+			nsec, rest, err := decodeOrderedInt32(rest)
+			if err != nil {
+				return nil, nil, err
+			}
+			return base.Time{Time: time.Unix(sec, int64(nsec)).UTC()}, rest, nil
+		},
+	}
+
+	// DurationCodec is the codec of base.Duration, registered as "duration".
+	// The JSON encoding is the duration string, e.g. "1m30s".
+	DurationCodec KeyCodec = &funcCodec{
+		check: func(key base.Comparable) bool { _, ok := key.(base.Duration); return ok },
+		appendBinary: func(dst []byte, key base.Comparable) ([]byte, error) {
+			return appendVarint(dst, int64(key.(base.Duration))), nil
+		},
+		decodeBinary: func(data []byte) (base.Comparable, error) {
+			x, err := decodeVarint(data)
+			return base.Duration(x), err
+		},
+		encodeJSON: func(key base.Comparable) ([]byte, error) {
+			return json.Marshal(key.String())
+		},
+		decodeJSON: func(data []byte) (base.Comparable, error) {
+			var s string
+			if err := json.Unmarshal(data, &s); err != nil {
+				return nil, err
+			}
+			d, err := time.ParseDuration(s)
+			return base.Duration(d), err
+		},
+		appendOrdered: func(dst []byte, key base.Comparable) ([]byte, error) {
+			return appendOrderedInt64(dst, int64(key.(base.Duration))), nil
+		},
+		decodeOrdered: func(data []byte) (base.Comparable, []byte, error) {
+			x, rest, err := decodeOrderedInt64(data)
+			return base.Duration(x), rest, err
+		},
+	}
+
+	// BigIntCodec is the codec of base.BigInt, registered as "bigint".
+	// The JSON encoding is the decimal string.
+	BigIntCodec KeyCodec = &funcCodec{
+		check: func(key base.Comparable) bool { _, ok := key.(base.BigInt); return ok },
+		appendBinary: func(dst []byte, key base.Comparable) ([]byte, error) {
+			x := bigIntOf(key)
+			dst = append(dst, byte(x.Sign()+1))
+			return append(dst, x.Bytes()...), nil
+		},
+		decodeBinary: func(data []byte) (base.Comparable, error) {
+			if len(data) == 0 || data[0] > 2 {
+				return nil, InvalidEncodingErr
+			}
+			x := new(big.Int).SetBytes(data[1:])
+			if data[0] == 0 {
+				x.Neg(x)
+			}
+			return base.BigInt{Int: x}, nil
+		},
+		encodeJSON: func(key base.Comparable) ([]byte, error) {
+			return json.Marshal(key.String())
+		},
+		decodeJSON: func(data []byte) (base.Comparable, error) {
+			var s string
+			if err := json.Unmarshal(data, &s); err != nil {
+				return nil, err
+			}
+			x, ok := new(big.Int).SetString(s, 10)
+			if !ok {
+				return nil, InvalidEncodingErr
+			}
+			return base.BigInt{Int: x}, nil
+		},
+		appendOrdered: appendOrderedBigInt,
+		decodeOrdered: decodeOrderedBigInt,
+	}
+)
+
+func bigIntOf(key base.Comparable) *big.Int {
+	if x := key.(base.BigInt).Int; x != nil {
+		return x
+	}
+	return new(big.Int)
+}
+
+// appendOrderedBigInt writes the sign class (0 negative, 1 zero, 2 positive),
+// then the length and the bytes of the magnitude, which are inverted for
+// negative numbers so that larger magnitudes sort first.
+func appendOrderedBigInt(dst []byte, key base.Comparable) ([]byte, error) {
+	x := bigIntOf(key)
+	sign := x.Sign()
+	dst = append(dst, byte(sign+1))
+	if sign == 0 {
+		return dst, nil
+	}
+
+	start := len(dst)
+	magnitude := x.Bytes()
+	dst = appendOrderedUint64(dst, uint64(len(magnitude)))
+	dst = append(dst, magnitude...)
+	if sign < 0 {
+		invert(dst[start:])
+	}
+	return dst, nil
+}
+
+func decodeOrderedBigInt(data []byte) (base.Comparable, []byte, error) {
+	if len(data) == 0 || data[0] > 2 {
+		return nil, nil, InvalidEncodingErr
+	}
+	sign := int(data[0]) - 1
+	if sign == 0 {
+		return base.BigInt{Int: new(big.Int)}, data[1:], nil
+	}
+
+	if len(data) < 9 {
+		return nil, nil, InvalidEncodingErr
+	}
+	header := append([]byte{}, data[1:9]...)
+	if sign < 0 {
+		invert(header)
+	}
+	length, _, _ := decodeOrderedUint64(header)
+	if uint64(len(data)-9) < length {
+		return nil, nil, InvalidEncodingErr
+	}
+
+	magnitude := append([]byte{}, data[9:9+length]...)
+	if sign < 0 {
+		invert(magnitude)
+	}
+	x := new(big.Int).SetBytes(magnitude)
+	if sign < 0 {
+		x.Neg(x)
+	}
+	return base.BigInt{Int: x}, data[9+length:], nil
+}
+
+func init() {
+	builtins := []struct {
+		name   string
+		sample base.Comparable
+		codec  KeyCodec
+	}{
+		{"int", base.Int(0), IntCodec},
+		{"rune", base.Rune(0), RuneCodec},
+		{"string", base.String(""), StringCodec},
+		{"float64", base.Float64(0), Float64Codec},
+		{"int64", base.Int64(0), Int64Codec},
+		{"uint64", base.Uint64(0), Uint64Codec},
+		{"bytes", base.Bytes(nil), BytesCodec},
+		{"time", base.Time{}, TimeCodec},
+		{"duration", base.Duration(0), DurationCodec},
+		{"bigint", base.BigInt{}, BigIntCodec},
+	}
+
+	for _, builtin := range builtins {
+		if err := Register(builtin.name, builtin.sample, builtin.codec); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package codec
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/aiden0z/kit/base"
+)
+
+func builtinKeys() [][]base.Comparable {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	return [][]base.Comparable{
+		{base.Int(0), base.Int(1), base.Int(-1), base.Int(math.MaxInt32), base.Int(-1 << 40), base.Int(255), base.Int(256)},
+		{base.Rune(0), base.Rune('a'), base.Rune('中'), base.Rune(-1), base.Rune(math.MaxInt32)},
+		{base.String(""), base.String("a"), base.String("a\x00"), base.String("a\x00b"), base.String("ab"), base.String("ÿ"), base.String("中文")},
+		{base.Float64(0), base.Float64(1.5), base.Float64(-1.5), base.Float64(math.NaN()), base.Float64(math.Inf(1)),
+			base.Float64(math.Inf(-1)), base.Float64(math.SmallestNonzeroFloat64), base.Float64(-math.MaxFloat64)},
+		{base.Int64(0), base.Int64(math.MaxInt64), base.Int64(math.MinInt64), base.Int64(-3)},
+		{base.Uint64(0), base.Uint64(1), base.Uint64(math.MaxUint64), base.Uint64(1 << 63)},
+		{base.Bytes{}, base.Bytes{0}, base.Bytes{0, 0}, base.Bytes{0, 1}, base.Bytes{1}, base.Bytes{0xff, 0xff}},
+		{base.Time{Time: time.Unix(0, 0).UTC()}, base.Time{Time: time.Date(2020, 2, 29, 12, 30, 0, 123, time.UTC)},
+			base.Time{Time: time.Date(1900, 1, 1, 0, 0, 0, 0, loc)}, base.Time{Time: time.Date(2020, 2, 29, 20, 30, 0, 124, loc)}},
+		{base.Duration(0), base.Duration(time.Minute + 30*time.Second), base.Duration(-time.Nanosecond), base.Duration(math.MaxInt64)},
+		{base.BigInt{Int: big.NewInt(0)}, base.BigInt{Int: big.NewInt(1)}, base.BigInt{Int: big.NewInt(-1)},
+			base.BigInt{Int: big.NewInt(256)}, base.BigInt{Int: big.NewInt(-256)}, base.BigInt{Int: new(big.Int).Lsh(big.NewInt(1), 100)},
+			base.BigInt{Int: new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 100))}, base.BigInt{}},
+	}
+}
+
+func TestBuiltinRoundTrip(t *testing.T) {
+	for _, keys := range builtinKeys() {
+		for _, key := range keys {
+			data, err := MarshalBinary(key)
+			if err != nil {
+				t.Fatalf("MarshalBinary %T %v error %v", key, key, err)
+			}
+			decoded, err := UnmarshalBinary(data)
+			if err != nil || decoded.CompareTo(key) != 0 {
+				t.Errorf("Got %v, %v expected %v for binary round trip", decoded, err, key)
+			}
+
+			data, err = MarshalJSON(key)
+			if err != nil {
+				t.Fatalf("MarshalJSON %T %v error %v", key, key, err)
+			}
+			decoded, err = UnmarshalJSON(data)
+			if err != nil || decoded.CompareTo(key) != 0 {
+				t.Errorf("Got %v, %v expected %v for JSON %s round trip", decoded, err, key, data)
+			}
+
+			name, _, _ := Lookup(key)
+			data, err = EncodeOrdered(key)
+			if err != nil {
+				t.Fatalf("EncodeOrdered %T %v error %v", key, key, err)
+			}
+			decoded, err = DecodeOrdered(name, data)
+			if err != nil || decoded.CompareTo(key) != 0 {
+				t.Errorf("Got %v, %v expected %v for ordered round trip", decoded, err, key)
+			}
+		}
+	}
+}
+
+func TestBuiltinJSON(t *testing.T) {
+	tests := []struct {
+		key      base.Comparable
+		expected string
+	}{
+		{base.Int(-5), `{"type":"int","key":-5}`},
+		{base.Rune('a'), `{"type":"rune","key":97}`},
+		{base.Float64(math.Inf(-1)), `{"type":"float64","key":"-Inf"}`},
+		{base.Duration(90 * time.Second), `{"type":"duration","key":"1m30s"}`},
+		{base.BigInt{Int: big.NewInt(-12)}, `{"type":"bigint","key":"-12"}`},
+		{base.Bytes("hi"), `{"type":"bytes","key":"aGk="}`},
+	}
+
+	for _, test := range tests {
+		data, err := MarshalJSON(test.key)
+		if err != nil || string(data) != test.expected {
+			t.Errorf("Got %s, %v expected %s", data, err, test.expected)
+		}
+	}
+}
+
+func sign(x int) int {
+	if x > 0 {
+		return 1
+	} else if x < 0 {
+		return -1
+	}
+	return 0
+}
+
+func TestTimeCodecError(t *testing.T) {
+	// the binary encoding of time.Time has no zone offset of -1 minute
+	key := base.Time{Time: time.Date(2020, 1, 1, 0, 0, 0, 0, time.FixedZone("", -60))}
+	if _, err := TimeCodec.AppendBinary(nil, key); err == nil {
+		t.Error("TimeCodec AppendBinary not return error for unsupported zone offset")
+	}
+	if _, err := MarshalBinary(key); err == nil {
+		t.Error("MarshalBinary not return error for unsupported zone offset")
+	}
+}
+
+func TestBuiltinOrdered(t *testing.T) {
+	for _, keys := range builtinKeys() {
+		for _, a := range keys {
+			for _, b := range keys {
+				x, _ := EncodeOrdered(a)
+				y, _ := EncodeOrdered(b)
+				if sign(bytes.Compare(x, y)) != sign(a.CompareTo(b)) {
+					t.Errorf("Got %v expected %v for ordered encoding of %v and %v",
+						bytes.Compare(x, y), sign(a.CompareTo(b)), a, b)
+				}
+			}
+		}
+	}
+}
+
+func TestBuiltinOrderedRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randomKey := []func() base.Comparable{
+		func() base.Comparable { return base.Int(r.Int63() - r.Int63()) },
+		func() base.Comparable { return base.Float64(r.NormFloat64() * 1e6) },
+		func() base.Comparable {
+			b := make([]byte, r.Intn(4))
+			for i := range b {
+				b[i] = byte(r.Intn(3))
+			}
+			return base.String(b)
+		},
+		func() base.Comparable {
+			return base.BigInt{Int: new(big.Int).Mul(big.NewInt(r.Int63()-r.Int63()), big.NewInt(r.Int63n(1<<20)))}
+		},
+	}
+
+	for _, next := range randomKey {
+		for i := 0; i < 1000; i++ {
+			a, b := next(), next()
+			x, _ := EncodeOrdered(a)
+			y, _ := EncodeOrdered(b)
+			if sign(bytes.Compare(x, y)) != sign(a.CompareTo(b)) {
+				t.Fatalf("Got %v expected %v for ordered encoding of %v and %v",
+					bytes.Compare(x, y), sign(a.CompareTo(b)), a, b)
+			}
+		}
+	}
+}
@@ -0,0 +1,207 @@
+// Package codec encodes and decodes base.Comparable keys, so that the contents
+// of trees are able to be persisted or shipped across services.
+// Every key type has a KeyCodec with three encodings:
+//   - binary: a compact encoding
+//   - JSON: a readable encoding
+//   - ordered: an order-preserving encoding, bytes.Compare of the encoded keys
+//     agrees with CompareTo of the keys, so encoded keys can be stored in any
+//     byte-ordered storage.
+//
+// Codecs of the built-in key types of base are registered by default, user
+// types are registered by Register.
+package codec
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/aiden0z/kit/base"
+)
+
+var (
+	// InvalidEncodingErr is returned when the data is not a valid encoding.
+	InvalidEncodingErr = errors.New("invalid encoding")
+	// UnregisteredTypeErr is returned when no codec is registered for the key type.
+	UnregisteredTypeErr = errors.New("unregistered key type")
+	// UnknownCodecErr is returned when no codec is registered with the name.
+	UnknownCodecErr = errors.New("unknown codec name")
+	// DuplicateCodecErr is returned when the name or the type is already registered.
+	DuplicateCodecErr = errors.New("duplicate codec")
+	// KeyTypeErr is returned when the key type does not match the codec.
+	KeyTypeErr = errors.New("key type not match the codec")
+)
+
+// KeyCodec describe the encodings of one key type.
+type KeyCodec interface {
+	// AppendBinary appends the binary encoding of key to dst.
+	AppendBinary(dst []byte, key base.Comparable) ([]byte, error)
+	// DecodeBinary decodes a key from its whole binary encoding.
+	DecodeBinary(data []byte) (base.Comparable, error)
+	// EncodeJSON returns the JSON encoding of key.
+	EncodeJSON(key base.Comparable) ([]byte, error)
+	// DecodeJSON decodes a key from its JSON encoding.
+	DecodeJSON(data []byte) (base.Comparable, error)
+	// AppendOrdered appends the order-preserving encoding of key to dst. The
+	// encoding must be prefix-free, no encoding is a prefix of another, so
+	// that encodings can be concatenated for composite keys.
+	AppendOrdered(dst []byte, key base.Comparable) ([]byte, error)
+	// DecodeOrdered decodes a key from the front of data and returns the rest.
+	DecodeOrdered(data []byte) (key base.Comparable, rest []byte, err error)
+}
+
+type registration struct {
+	name  string
+	codec KeyCodec
+}
+
+var (
+	registryMu sync.RWMutex
+	byType     = make(map[reflect.Type]registration)
+	byName     = make(map[string]KeyCodec)
+)
+
+// Register the codec for the type of sample under name, the name is written
+// into the self-describing binary and JSON encodings and must be stable.
+func Register(name string, sample base.Comparable, codec KeyCodec) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	t := reflect.TypeOf(sample)
+	if _, ok := byName[name]; ok {
+		return DuplicateCodecErr
+	}
+	if _, ok := byType[t]; ok {
+		return DuplicateCodecErr
+	}
+
+	byType[t] = registration{name: name, codec: codec}
+	byName[name] = codec
+	return nil
+}
+
+// Lookup returns the name and the codec registered for the type of key.
+func Lookup(key base.Comparable) (name string, codec KeyCodec, found bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	r, found := byType[reflect.TypeOf(key)]
+	return r.name, r.codec, found
+}
+
+// LookupName returns the codec registered with name.
+func LookupName(name string) (codec KeyCodec, found bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	codec, found = byName[name]
+	return
+}
+
+func lookup(key base.Comparable) (string, KeyCodec, error) {
+	name, codec, found := Lookup(key)
+	if !found {
+		return "", nil, fmt.Errorf("%w: %T", UnregisteredTypeErr, key)
+	}
+	return name, codec, nil
+}
+
+func lookupName(name string) (KeyCodec, error) {
+	codec, found := LookupName(name)
+	if !found {
+		return nil, fmt.Errorf("%w: %q", UnknownCodecErr, name)
+	}
+	return codec, nil
+}
+
+// MarshalBinary returns the self-describing binary encoding of key, the codec
+// name is written before the encoded key.
+func MarshalBinary(key base.Comparable) ([]byte, error) {
+	name, codec, err := lookup(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data := appendUvarint(nil, uint64(len(name)))
+	data = append(data, name...)
+	return codec.AppendBinary(data, key)
+}
+
+// UnmarshalBinary decodes a key encoded by MarshalBinary.
+func UnmarshalBinary(data []byte) (base.Comparable, error) {
+	length, n := binary.Uvarint(data)
+	if n <= 0 || uint64(len(data)-n) < length {
+		return nil, InvalidEncodingErr
+	}
+
+	codec, err := lookupName(string(data[n : n+int(length)]))
+	if err != nil {
+		return nil, err
+	}
+	return codec.DecodeBinary(data[n+int(length):])
+}
+
+type jsonKey struct {
+	Type string          `json:"type"`
+	Key  json.RawMessage `json:"key"`
+}
+
+// MarshalJSON returns the self-describing JSON encoding of key,
+// {"type": name, "key": encoded key}.
+func MarshalJSON(key base.Comparable) ([]byte, error) {
+	name, codec, err := lookup(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := codec.EncodeJSON(key)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonKey{Type: name, Key: data})
+}
+
+// UnmarshalJSON decodes a key encoded by MarshalJSON.
+func UnmarshalJSON(data []byte) (base.Comparable, error) {
+	var k jsonKey
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, err
+	}
+
+	codec, err := lookupName(k.Type)
+	if err != nil {
+		return nil, err
+	}
+	return codec.DecodeJSON(k.Key)
+}
+
+// EncodeOrdered returns the order-preserving encoding of key. The encoding
+// does not contain the codec name, encoded keys are ordered among keys of
+// the same type only.
+func EncodeOrdered(key base.Comparable) ([]byte, error) {
+	_, codec, err := lookup(key)
+	if err != nil {
+		return nil, err
+	}
+	return codec.AppendOrdered(nil, key)
+}
+
+// DecodeOrdered decodes a key encoded by EncodeOrdered with the codec registered with name.
+func DecodeOrdered(name string, data []byte) (base.Comparable, error) {
+	codec, err := lookupName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	key, rest, err := codec.DecodeOrdered(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, InvalidEncodingErr
+	}
+	return key, nil
+}
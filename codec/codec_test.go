@@ -0,0 +1,147 @@
+package codec
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aiden0z/kit/base"
+)
+
+// version is a user key type registered in the tests.
+type version int
+
+func (v version) CompareTo(o base.Comparable) int {
+	other, ok := o.(version)
+	if !ok {
+		return 1
+	}
+	return int(v) - int(other)
+}
+
+func (v version) String() string {
+	return base.Int(v).String()
+}
+
+// versionCodec encodes version as base.Int.
+type versionCodec struct{}
+
+func (versionCodec) AppendBinary(dst []byte, key base.Comparable) ([]byte, error) {
+	return IntCodec.AppendBinary(dst, base.Int(key.(version)))
+}
+
+func (versionCodec) DecodeBinary(data []byte) (base.Comparable, error) {
+	key, err := IntCodec.DecodeBinary(data)
+	if err != nil {
+		return nil, err
+	}
+	return version(key.(base.Int)), nil
+}
+
+func (versionCodec) EncodeJSON(key base.Comparable) ([]byte, error) {
+	return IntCodec.EncodeJSON(base.Int(key.(version)))
+}
+
+func (versionCodec) DecodeJSON(data []byte) (base.Comparable, error) {
+	key, err := IntCodec.DecodeJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return version(key.(base.Int)), nil
+}
+
+func (versionCodec) AppendOrdered(dst []byte, key base.Comparable) ([]byte, error) {
+	return IntCodec.AppendOrdered(dst, base.Int(key.(version)))
+}
+
+func (versionCodec) DecodeOrdered(data []byte) (base.Comparable, []byte, error) {
+	key, rest, err := IntCodec.DecodeOrdered(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return version(key.(base.Int)), rest, nil
+}
+
+func TestRegister(t *testing.T) {
+	if _, err := MarshalBinary(version(1)); !errors.Is(err, UnregisteredTypeErr) {
+		t.Errorf("Got %v expected %v for unregistered type", err, UnregisteredTypeErr)
+	}
+
+	if err := Register("test.version", version(0), versionCodec{}); err != nil {
+		t.Fatalf("Register error %v", err)
+	}
+	// unregister, so that the test is able to run again
+	defer func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		delete(byName, "test.version")
+		delete(byType, reflect.TypeOf(version(0)))
+	}()
+	if err := Register("test.version", base.Int(0), versionCodec{}); !errors.Is(err, DuplicateCodecErr) {
+		t.Errorf("Got %v expected %v for duplicate name", err, DuplicateCodecErr)
+	}
+	if err := Register("test.other", version(0), versionCodec{}); !errors.Is(err, DuplicateCodecErr) {
+		t.Errorf("Got %v expected %v for duplicate type", err, DuplicateCodecErr)
+	}
+
+	if name, _, found := Lookup(version(3)); !found || name != "test.version" {
+		t.Errorf("Got %v, %v expected %v, %v", name, found, "test.version", true)
+	}
+	if _, found := LookupName("test.other"); found {
+		t.Error("Lookup a non registered name")
+	}
+
+	data, err := MarshalBinary(version(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := UnmarshalBinary(data)
+	if err != nil || key != version(42) {
+		t.Errorf("Got %v, %v expected %v for binary round trip", key, err, version(42))
+	}
+
+	data, err = MarshalJSON(version(-7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"type":"test.version","key":-7}` {
+		t.Errorf("Got %s expected %s", data, `{"type":"test.version","key":-7}`)
+	}
+	key, err = UnmarshalJSON(data)
+	if err != nil || key != version(-7) {
+		t.Errorf("Got %v, %v expected %v for JSON round trip", key, err, version(-7))
+	}
+
+	data, err = EncodeOrdered(version(9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err = DecodeOrdered("test.version", data)
+	if err != nil || key != version(9) {
+		t.Errorf("Got %v, %v expected %v for ordered round trip", key, err, version(9))
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	if _, err := UnmarshalBinary([]byte{5, 'i', 'n'}); !errors.Is(err, InvalidEncodingErr) {
+		t.Errorf("Got %v expected %v for truncated name", err, InvalidEncodingErr)
+	}
+	if _, err := UnmarshalBinary([]byte{3, 'f', 'o', 'o', 1}); !errors.Is(err, UnknownCodecErr) {
+		t.Errorf("Got %v expected %v for unknown name", err, UnknownCodecErr)
+	}
+	if _, err := UnmarshalJSON([]byte(`{"type":"foo","key":1}`)); !errors.Is(err, UnknownCodecErr) {
+		t.Errorf("Got %v expected %v for unknown name", err, UnknownCodecErr)
+	}
+
+	data, _ := EncodeOrdered(base.Int(1))
+	if _, err := DecodeOrdered("int", append(data, 0)); !errors.Is(err, InvalidEncodingErr) {
+		t.Errorf("Got %v expected %v for trailing bytes", err, InvalidEncodingErr)
+	}
+	if _, err := DecodeOrdered("int", data[:4]); !errors.Is(err, InvalidEncodingErr) {
+		t.Errorf("Got %v expected %v for truncated data", err, InvalidEncodingErr)
+	}
+
+	if _, err := IntCodec.AppendBinary(nil, base.Rune('a')); !errors.Is(err, KeyTypeErr) {
+		t.Errorf("Got %v expected %v for mismatched key", err, KeyTypeErr)
+	}
+}
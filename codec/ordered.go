@@ -0,0 +1,161 @@
+package codec
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// Helpers of the binary and the order-preserving encodings.
+// Fixed-size integers are written in big endian with the sign bit flipped,
+// so that negative numbers sort before positive ones. Byte strings are
+// escaped and terminated: 0x00 is written as 0x00 0xff and the string ends
+// with 0x00 0x01, which keeps the order and makes the encoding prefix-free.
+
+const (
+	escape     byte = 0x00
+	escaped00  byte = 0xff
+	terminator byte = 0x01
+)
+
+func appendUvarint(dst []byte, x uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], x)
+	return append(dst, buf[:n]...)
+}
+
+func appendVarint(dst []byte, x int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(buf[:], x)
+	return append(dst, buf[:n]...)
+}
+
+// decodeVarint decodes a varint which must be the whole data.
+func decodeVarint(data []byte) (int64, error) {
+	x, n := binary.Varint(data)
+	if n <= 0 || n != len(data) {
+		return 0, InvalidEncodingErr
+	}
+	return x, nil
+}
+
+// decodeUvarint decodes an uvarint which must be the whole data.
+func decodeUvarint(data []byte) (uint64, error) {
+	x, n := binary.Uvarint(data)
+	if n <= 0 || n != len(data) {
+		return 0, InvalidEncodingErr
+	}
+	return x, nil
+}
+
+func appendOrderedUint64(dst []byte, x uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], x)
+	return append(dst, buf[:]...)
+}
+
+func decodeOrderedUint64(data []byte) (uint64, []byte, error) {
+	if len(data) < 8 {
+		return 0, nil, InvalidEncodingErr
+	}
+	return binary.BigEndian.Uint64(data), data[8:], nil
+}
+
+func appendOrderedInt64(dst []byte, x int64) []byte {
+	return appendOrderedUint64(dst, uint64(x)^(1<<63))
+}
+
+func decodeOrderedInt64(data []byte) (int64, []byte, error) {
+	x, rest, err := decodeOrderedUint64(data)
+	return int64(x ^ (1 << 63)), rest, err
+}
+
+func appendOrderedInt32(dst []byte, x int32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(x)^(1<<31))
+	return append(dst, buf[:]...)
+}
+
+func decodeOrderedInt32(data []byte) (int32, []byte, error) {
+	if len(data) < 4 {
+		return 0, nil, InvalidEncodingErr
+	}
+	return int32(binary.BigEndian.Uint32(data) ^ (1 << 31)), data[4:], nil
+}
+
+// appendOrderedFloat64 follows the order of base.Float64, NaN is the least
+// and -0 equals to +0.
+func appendOrderedFloat64(dst []byte, f float64) []byte {
+	if math.IsNaN(f) {
+		return appendOrderedUint64(dst, 0)
+	}
+	if f == 0 {
+		f = 0
+	}
+
+	bits := math.Float64bits(f)
+	if bits&(1<<63) != 0 {
+		bits = ^bits
+	} else {
+		bits |= 1 << 63
+	}
+	return appendOrderedUint64(dst, bits)
+}
+
+func decodeOrderedFloat64(data []byte) (float64, []byte, error) {
+	bits, rest, err := decodeOrderedUint64(data)
+	if err != nil {
+		return 0, nil, err
+	}
+	if bits == 0 {
+		return math.NaN(), rest, nil
+	}
+
+	if bits&(1<<63) != 0 {
+		bits &^= 1 << 63
+	} else {
+		bits = ^bits
+	}
+	return math.Float64frombits(bits), rest, nil
+}
+
+func appendOrderedBytes(dst []byte, b []byte) []byte {
+	for _, c := range b {
+		if c == escape {
+			dst = append(dst, escape, escaped00)
+		} else {
+			dst = append(dst, c)
+		}
+	}
+	return append(dst, escape, terminator)
+}
+
+func decodeOrderedBytes(data []byte) ([]byte, []byte, error) {
+	b := []byte{}
+	for i := 0; i < len(data); i++ {
+		if data[i] != escape {
+			b = append(b, data[i])
+			continue
+		}
+
+		if i+1 >= len(data) {
+			break
+		}
+		switch data[i+1] {
+		case escaped00:
+			b = append(b, escape)
+			i++
+		case terminator:
+			return b, data[i+2:], nil
+		default:
+			return nil, nil, InvalidEncodingErr
+		}
+	}
+	return nil, nil, InvalidEncodingErr
+}
+
+// invert inverts the bytes so that the order is reversed.
+func invert(b []byte) {
+	for i := range b {
+		b[i] = ^b[i]
+	}
+}
@@ -0,0 +1,222 @@
+package codec
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aiden0z/kit/base"
+)
+
+// markers of the ordered encoding of tuple fields
+const (
+	tupleEnd       byte = 0x00
+	tupleNullFirst byte = 0x01
+	tupleValue     byte = 0x02
+	tupleNullLast  byte = 0x03
+)
+
+// TupleCodec is the codec of *base.Tuple whose fields are encoded by fixed
+// codecs in position. Tuples may have fewer fields than the codec, but not more.
+// It is not registered since all tuples share one type while the field
+// types vary, so use the codec of the tuple schema directly.
+type TupleCodec struct {
+	orders []base.TupleOrder
+	fields []KeyCodec
+}
+
+// NewTupleCodec creates a TupleCodec, fields[i] encodes the i-th field and
+// orders[i] is its order, the decoded tuples are ordered by orders too.
+func NewTupleCodec(orders []base.TupleOrder, fields ...KeyCodec) *TupleCodec {
+	return &TupleCodec{orders: orders, fields: fields}
+}
+
+func (c *TupleCodec) order(i int) base.TupleOrder {
+	if i < len(c.orders) {
+		return c.orders[i]
+	}
+	return base.TupleOrder{}
+}
+
+func (c *TupleCodec) tuple(key base.Comparable) (*base.Tuple, error) {
+	t, ok := key.(*base.Tuple)
+	if !ok || len(t.Values) > len(c.fields) {
+		return nil, fmt.Errorf("%w: %v", KeyTypeErr, key)
+	}
+	return t, nil
+}
+
+// AppendBinary writes the number of fields, then every field as a length
+// prefixed binary encoding, NULL is written as length 0 with a leading marker.
+func (c *TupleCodec) AppendBinary(dst []byte, key base.Comparable) ([]byte, error) {
+	t, err := c.tuple(key)
+	if err != nil {
+		return nil, err
+	}
+
+	dst = appendUvarint(dst, uint64(len(t.Values)))
+	for i, v := range t.Values {
+		if v == nil {
+			dst = append(dst, 0)
+			continue
+		}
+		field, err := c.fields[i].AppendBinary(nil, v)
+		if err != nil {
+			return nil, err
+		}
+		dst = append(dst, 1)
+		dst = appendUvarint(dst, uint64(len(field)))
+		dst = append(dst, field...)
+	}
+	return dst, nil
+}
+
+func (c *TupleCodec) DecodeBinary(data []byte) (base.Comparable, error) {
+	count, n := binary.Uvarint(data)
+	if n <= 0 || count > uint64(len(c.fields)) {
+		return nil, InvalidEncodingErr
+	}
+	data = data[n:]
+
+	values := make([]base.Comparable, count)
+	for i := range values {
+		if len(data) == 0 {
+			return nil, InvalidEncodingErr
+		}
+		marker := data[0]
+		data = data[1:]
+		if marker == 0 {
+			continue
+		}
+
+		length, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < length {
+			return nil, InvalidEncodingErr
+		}
+		v, err := c.fields[i].DecodeBinary(data[n : n+int(length)])
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+		data = data[n+int(length):]
+	}
+
+	if len(data) != 0 {
+		return nil, InvalidEncodingErr
+	}
+	return base.NewTupleWithOrders(c.orders, values...), nil
+}
+
+// EncodeJSON returns a JSON array of the fields, NULL is null.
+func (c *TupleCodec) EncodeJSON(key base.Comparable) ([]byte, error) {
+	t, err := c.tuple(key)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]json.RawMessage, len(t.Values))
+	for i, v := range t.Values {
+		if v == nil {
+			fields[i] = json.RawMessage("null")
+			continue
+		}
+		if fields[i], err = c.fields[i].EncodeJSON(v); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(fields)
+}
+
+func (c *TupleCodec) DecodeJSON(data []byte) (base.Comparable, error) {
+	var fields []json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if len(fields) > len(c.fields) {
+		return nil, InvalidEncodingErr
+	}
+
+	values := make([]base.Comparable, len(fields))
+	for i, field := range fields {
+		if string(field) == "null" {
+			continue
+		}
+		v, err := c.fields[i].DecodeJSON(field)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return base.NewTupleWithOrders(c.orders, values...), nil
+}
+
+// AppendOrdered writes a marker before every field, NULL fields are a marker
+// only, and the encoding of a descending field is inverted. The tuple ends
+// with the least marker so that a shorter tuple sorts first.
+func (c *TupleCodec) AppendOrdered(dst []byte, key base.Comparable) ([]byte, error) {
+	t, err := c.tuple(key)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, v := range t.Values {
+		order := c.order(i)
+		if v == nil {
+			if order.NullsLast {
+				dst = append(dst, tupleNullLast)
+			} else {
+				dst = append(dst, tupleNullFirst)
+			}
+			continue
+		}
+
+		dst = append(dst, tupleValue)
+		start := len(dst)
+		if dst, err = c.fields[i].AppendOrdered(dst, v); err != nil {
+			return nil, err
+		}
+		if order.Descending {
+			invert(dst[start:])
+		}
+	}
+	return append(dst, tupleEnd), nil
+}
+
+func (c *TupleCodec) DecodeOrdered(data []byte) (base.Comparable, []byte, error) {
+	values := []base.Comparable{}
+	for i := 0; ; i++ {
+		if len(data) == 0 {
+			return nil, nil, InvalidEncodingErr
+		}
+		marker := data[0]
+		data = data[1:]
+
+		switch marker {
+		case tupleEnd:
+			return base.NewTupleWithOrders(c.orders, values...), data, nil
+		case tupleNullFirst, tupleNullLast:
+			if i >= len(c.fields) {
+				return nil, nil, InvalidEncodingErr
+			}
+			values = append(values, nil)
+		case tupleValue:
+			if i >= len(c.fields) {
+				return nil, nil, InvalidEncodingErr
+			}
+			field := data
+			if c.order(i).Descending {
+				// the length of the field is unknown, decode an inverted copy
+				field = append([]byte{}, data...)
+				invert(field)
+			}
+			v, rest, err := c.fields[i].DecodeOrdered(field)
+			if err != nil {
+				return nil, nil, err
+			}
+			values = append(values, v)
+			data = data[len(data)-len(rest):]
+		default:
+			return nil, nil, InvalidEncodingErr
+		}
+	}
+}
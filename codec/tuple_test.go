@@ -0,0 +1,68 @@
+package codec
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aiden0z/kit/base"
+)
+
+func TestTupleCodec(t *testing.T) {
+	orders := []base.TupleOrder{base.Asc, base.Desc, {NullsLast: true}}
+	codec := NewTupleCodec(orders, StringCodec, IntCodec, IntCodec)
+	tuples := []*base.Tuple{
+		base.NewTupleWithOrders(orders),
+		base.NewTupleWithOrders(orders, base.String("a")),
+		base.NewTupleWithOrders(orders, base.String("a"), base.Int(3)),
+		base.NewTupleWithOrders(orders, base.String("a"), base.Int(1), base.Int(1)),
+		base.NewTupleWithOrders(orders, base.String("a"), base.Int(1), nil),
+		base.NewTupleWithOrders(orders, base.String("a"), nil, base.Int(1)),
+		base.NewTupleWithOrders(orders, base.String("a\x00"), base.Int(1)),
+		base.NewTupleWithOrders(orders, base.String("b"), base.Int(-1), base.Int(0)),
+		base.NewTupleWithOrders(orders, nil, base.Int(0)),
+	}
+
+	for _, tuple := range tuples {
+		data, err := codec.AppendBinary(nil, tuple)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := codec.DecodeBinary(data)
+		if err != nil || decoded.CompareTo(tuple) != 0 || decoded.(*base.Tuple).Len() != tuple.Len() {
+			t.Errorf("Got %v, %v expected %v for binary round trip", decoded, err, tuple)
+		}
+
+		data, err = codec.EncodeJSON(tuple)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err = codec.DecodeJSON(data)
+		if err != nil || decoded.CompareTo(tuple) != 0 {
+			t.Errorf("Got %v, %v expected %v for JSON %s round trip", decoded, err, tuple, data)
+		}
+
+		data, err = codec.AppendOrdered(nil, tuple)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, rest, err := codec.DecodeOrdered(append(data, 0xff))
+		if err != nil || decoded.CompareTo(tuple) != 0 || len(rest) != 1 {
+			t.Errorf("Got %v, %v expected %v for ordered round trip", decoded, err, tuple)
+		}
+	}
+
+	for _, a := range tuples {
+		for _, b := range tuples {
+			x, _ := codec.AppendOrdered(nil, a)
+			y, _ := codec.AppendOrdered(nil, b)
+			if sign(bytes.Compare(x, y)) != sign(a.CompareTo(b)) {
+				t.Errorf("Got %v expected %v for ordered encoding of %v and %v",
+					bytes.Compare(x, y), a.CompareTo(b), a, b)
+			}
+		}
+	}
+
+	if _, err := codec.AppendOrdered(nil, base.NewTuple(base.Int(1), base.Int(2), base.Int(3), base.Int(4))); err == nil {
+		t.Error("Encode a tuple with too many fields")
+	}
+}
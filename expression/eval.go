@@ -0,0 +1,91 @@
+package expression
+
+import (
+	"errors"
+
+	"github.com/aiden0z/kit/tree/binarytree"
+)
+
+// Variables resolves the values of identifiers.
+type Variables interface {
+	Lookup(name string) (value interface{}, found bool)
+}
+
+// MapVariables is Variables of a map.
+type MapVariables map[string]interface{}
+
+func (m MapVariables) Lookup(name string) (value interface{}, found bool) {
+	value, found = m[name]
+	return
+}
+
+// VariablesFunc adapts a function to Variables.
+type VariablesFunc func(name string) (value interface{}, found bool)
+
+func (f VariablesFunc) Lookup(name string) (value interface{}, found bool) {
+	return f(name)
+}
+
+// Evaluate evaluates the expression tree, numbers are float64 and
+// identifiers are resolved by vars, which may be nil if there is no
+// identifier. Errors are returned with the position of the token.
+func Evaluate(tree *binarytree.Btree, vars Variables) (interface{}, error) {
+	if tree == nil {
+		return nil, errorAt(0, MissingOperandErr)
+	}
+
+	token := tree.Element.(*Token)
+	switch token.Kind {
+	case NumberToken:
+		return token.Value, nil
+
+	case IdentifierToken:
+		if vars != nil {
+			if value, found := vars.Lookup(token.Text); found {
+				return value, nil
+			}
+		}
+		return nil, errorAt(token.Pos, UndefinedVariableErr)
+
+	case OperatorToken:
+		if token.Operator == nil {
+			return nil, errorAt(token.Pos, UnexpectedTokenErr)
+		}
+
+		var operands []interface{}
+		if !token.Operator.Unary {
+			left, err := Evaluate(tree.Left, vars)
+			if err != nil {
+				return nil, err
+			}
+			operands = append(operands, left)
+		}
+		right, err := Evaluate(tree.Right, vars)
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, right)
+
+		result, err := token.Operator.Apply(operands...)
+		if err != nil {
+			var positioned *Error
+			if errors.As(err, &positioned) {
+				return nil, err
+			}
+			return nil, errorAt(token.Pos, err)
+		}
+		return result, nil
+
+	default:
+		return nil, errorAt(token.Pos, UnexpectedTokenErr)
+	}
+}
+
+// Eval parses and evaluates the infix expression.
+func Eval(expr string, ops *Operators, vars Variables) (interface{}, error) {
+	tree, err := Parse(expr, ops)
+	if err != nil {
+		return nil, err
+	}
+	return Evaluate(tree, vars)
+}
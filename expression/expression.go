@@ -0,0 +1,53 @@
+// Package expression parses and evaluates infix expressions, it is the core
+// of a small rules engine.
+// An expression is converted to postfix by the shunting-yard algorithm, the
+// postfix is built into an expression tree of binarytree.Btree whose elements
+// are *Token, and the tree is evaluated with the operators of the table and
+// the variables. Operators are pluggable, DefaultOperators has the arithmetic,
+// comparison and logical operators.
+//
+// Reference:
+// - https://en.wikipedia.org/wiki/Shunting-yard_algorithm
+package expression
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// UnexpectedTokenErr is returned when a token is not expected at its position.
+	UnexpectedTokenErr = errors.New("unexpected token")
+	// MissingOperandErr is returned when an operator has no operand.
+	MissingOperandErr = errors.New("missing operand")
+	// MismatchedParenthesisErr is returned when parentheses are not paired.
+	MismatchedParenthesisErr = errors.New("mismatched parenthesis")
+	// UndefinedVariableErr is returned when a variable has no value.
+	UndefinedVariableErr = errors.New("undefined variable")
+	// OperandTypeErr is returned when an operand is of wrong type for the operator.
+	OperandTypeErr = errors.New("invalid operand type")
+	// DivisionByZeroErr is returned when the divisor is zero.
+	DivisionByZeroErr = errors.New("division by zero")
+	// InvalidOperatorErr is returned when an operator can not be added to a table.
+	InvalidOperatorErr = errors.New("invalid operator")
+)
+
+// Error describe an error at a position of the expression, the position is
+// the byte offset of the token which causes the error.
+type Error struct {
+	Pos int
+	Err error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v at position %d", e.Err, e.Pos)
+}
+
+// Unwrap makes errors.Is(err, UnexpectedTokenErr) work for example.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func errorAt(pos int, err error) *Error {
+	return &Error{Pos: pos, Err: err}
+}
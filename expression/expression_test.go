@@ -0,0 +1,246 @@
+package expression
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/aiden0z/kit/tree/binarytree"
+)
+
+func postfixString(tokens []*Token) string {
+	texts := make([]string, len(tokens))
+	for i, token := range tokens {
+		texts[i] = token.Text
+	}
+	return strings.Join(texts, " ")
+}
+
+func TestToPostfix(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected string
+	}{
+		{"1 + 2 * 3", "1 2 3 * +"},
+		{"(1 + 2) * 3", "1 2 + 3 *"},
+		{"2 ^ 3 ^ 2", "2 3 2 ^ ^"},
+		{"1 - 2 - 3", "1 2 - 3 -"},
+		{"-2 ^ 2", "2 2 ^ -"},
+		{"-a * b", "a - b *"},
+		{"a >= 1 && !(b || c)", "a 1 >= b c || ! &&"},
+		{"x.y != 1.5e3", "x.y 1.5e3 !="},
+	}
+
+	ops := DefaultOperators()
+	for _, test := range tests {
+		postfix, err := ToPostfix(test.expr, ops)
+		if err != nil {
+			t.Errorf("ToPostfix %q error %v", test.expr, err)
+			continue
+		}
+		if s := postfixString(postfix); s != test.expected {
+			t.Errorf("Got %q expected %q for %q", s, test.expected, test.expr)
+		}
+	}
+}
+
+func TestEval(t *testing.T) {
+	vars := MapVariables{"a": 3.0, "b": 4.0, "ok": true, "name": "kit"}
+	tests := []struct {
+		expr     string
+		expected interface{}
+	}{
+		{"1 + 2 * 3", 7.0},
+		{"(1 + 2) * 3", 9.0},
+		{"2 ^ 3 ^ 2", 512.0},
+		{"10 - 4 - 3", 3.0},
+		{"-2 ^ 2", -4.0},
+		{"(-2) ^ 2", 4.0},
+		{"2 * -a", -6.0},
+		{"a * a + b * b == 25", true},
+		{"7 % 4", 3.0},
+		{"ok && a < b", true},
+		{"!ok || a > b", false},
+		{"name == name", true},
+	}
+
+	ops := DefaultOperators()
+	for _, test := range tests {
+		result, err := Eval(test.expr, ops, vars)
+		if err != nil || result != test.expected {
+			t.Errorf("Got %v, %v expected %v for %q", result, err, test.expected, test.expr)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		pos  int
+		err  error
+	}{
+		{"1 +", 3, MissingOperandErr},
+		{"", 0, MissingOperandErr},
+		{"* 2", 0, MissingOperandErr},
+		{"(1 + 2", 0, MismatchedParenthesisErr},
+		{"1 + 2)", 5, MismatchedParenthesisErr},
+		{"()", 1, MissingOperandErr},
+		{"1 2", 2, UnexpectedTokenErr},
+		{"a (b)", 2, UnexpectedTokenErr},
+		{"1 + $", 4, UnexpectedTokenErr},
+		{"a !", 2, UnexpectedTokenErr},
+		{"1 / (a - a)", 2, DivisionByZeroErr},
+		{"1 + c", 4, UndefinedVariableErr},
+		{"ok + 1", 3, OperandTypeErr},
+		{"!a", 0, OperandTypeErr},
+		{"a == ok", 2, OperandTypeErr},
+	}
+
+	ops := DefaultOperators()
+	vars := MapVariables{"a": 1.0, "ok": true}
+	for _, test := range tests {
+		_, err := Eval(test.expr, ops, vars)
+		var positioned *Error
+		if !errors.As(err, &positioned) || !errors.Is(err, test.err) || positioned.Pos != test.pos {
+			t.Errorf("Got %v expected %v at position %d for %q", err, test.err, test.pos, test.expr)
+		}
+	}
+}
+
+func TestBuildTreeErrors(t *testing.T) {
+	ops := DefaultOperators()
+	plus, _ := ops.Binary("+")
+	one := &Token{Kind: NumberToken, Text: "1", Value: 1}
+	two := &Token{Kind: NumberToken, Text: "2", Pos: 2, Value: 2}
+	add := &Token{Kind: OperatorToken, Text: "+", Pos: 4, Operator: plus}
+
+	if _, err := BuildTree([]*Token{one, add}); !errors.Is(err, MissingOperandErr) {
+		t.Errorf("Got %v expected %v", err, MissingOperandErr)
+	}
+	if _, err := BuildTree([]*Token{one, two}); !errors.Is(err, UnexpectedTokenErr) {
+		t.Errorf("Got %v expected %v", err, UnexpectedTokenErr)
+	}
+	tree, err := BuildTree([]*Token{one, two, add})
+	if err != nil || tree.Element != add || tree.Left.Element != one || tree.Right.Element != two {
+		t.Errorf("BuildTree error %v", err)
+	}
+}
+
+func TestCustomOperators(t *testing.T) {
+	ops := NewOperators()
+	if err := ops.Add(&Operator{Symbol: "max", Apply: func(operands ...interface{}) (interface{}, error) {
+		return nil, nil
+	}}); !errors.Is(err, InvalidOperatorErr) {
+		t.Errorf("Got %v expected %v for operator with letters", err, InvalidOperatorErr)
+	}
+
+	ops.Add(NumberOperator("<>", 1, false, false, func(x ...float64) (interface{}, error) {
+		return math.Max(x[0], x[1]), nil
+	}))
+	ops.Add(NumberOperator("<", 2, false, false, func(x ...float64) (interface{}, error) {
+		return math.Min(x[0], x[1]), nil
+	}))
+	ops.Add(NumberOperator("~", 3, true, false, func(x ...float64) (interface{}, error) {
+		return math.Floor(x[0]), nil
+	}))
+
+	result, err := Eval("1 < 5 <> ~2.5", ops, nil)
+	if err != nil || result != 2.0 {
+		t.Errorf("Got %v, %v expected %v", result, err, 2.0)
+	}
+
+	counter := 0
+	vars := VariablesFunc(func(name string) (interface{}, bool) {
+		counter++
+		return float64(len(name)), true
+	})
+	result, err = Eval("abc <> ab", ops, vars)
+	if err != nil || result != 3.0 || counter != 2 {
+		t.Errorf("Got %v, %v expected %v", result, err, 3.0)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected string
+	}{
+		{"((1 + 2)) * 3", "(1 + 2) * 3"},
+		{"1 + (2 * 3)", "1 + 2 * 3"},
+		{"(1 - 2) - 3", "1 - 2 - 3"},
+		{"1 - (2 - 3)", "1 - (2 - 3)"},
+		{"(2 ^ 3) ^ 2", "(2 ^ 3) ^ 2"},
+		{"2 ^ (3 ^ 2)", "2 ^ 3 ^ 2"},
+		{"-(2 ^ 2)", "-2 ^ 2"},
+		{"(-2) ^ 2", "(-2) ^ 2"},
+		{"-(a + b)", "-(a + b)"},
+		{"a * (-b)", "a * -b"},
+		{"!(a && b) || (c)", "!(a && b) || c"},
+	}
+
+	ops := DefaultOperators()
+	for _, test := range tests {
+		tree, err := Parse(test.expr, ops)
+		if err != nil {
+			t.Errorf("Parse %q error %v", test.expr, err)
+			continue
+		}
+		if s := Format(tree); s != test.expected {
+			t.Errorf("Got %q expected %q for %q", s, test.expected, test.expr)
+		}
+	}
+}
+
+// randomTree builds a random arithmetic expression tree.
+func randomTree(r *rand.Rand, ops *Operators, depth int) *binarytree.Btree {
+	if depth == 0 || r.Intn(4) == 0 {
+		return &binarytree.Btree{Element: &Token{Kind: IdentifierToken, Text: string(rune('a' + r.Intn(3)))}}
+	}
+	if r.Intn(5) == 0 {
+		op, _ := ops.Unary("-")
+		return &binarytree.Btree{
+			Element: &Token{Kind: OperatorToken, Text: op.Symbol, Operator: op},
+			Right:   randomTree(r, ops, depth-1),
+		}
+	}
+
+	symbols := []string{"+", "-", "*", "^"}
+	op, _ := ops.Binary(symbols[r.Intn(len(symbols))])
+	return &binarytree.Btree{
+		Element: &Token{Kind: OperatorToken, Text: op.Symbol, Operator: op},
+		Left:    randomTree(r, ops, depth-1),
+		Right:   randomTree(r, ops, depth-1),
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	ops := DefaultOperators()
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		tree := randomTree(r, ops, 5)
+		s := Format(tree)
+		parsed, err := Parse(s, ops)
+		if err != nil {
+			t.Fatalf("Parse %q error %v", s, err)
+		}
+
+		expected := postfixOf(tree)
+		if actual := postfixOf(parsed); actual != expected {
+			t.Fatalf("Got %q expected %q for %q", actual, expected, s)
+		}
+	}
+}
+
+func postfixOf(tree *binarytree.Btree) string {
+	texts := []string{}
+	for _, node := range tree.PostOrder() {
+		text := node.Element.String()
+		if op := operatorOf(node); op != nil && op.Unary {
+			text = "u" + text
+		}
+		texts = append(texts, text)
+	}
+	return strings.Join(texts, " ")
+}
@@ -0,0 +1,70 @@
+package expression
+
+import (
+	"bytes"
+
+	"github.com/aiden0z/kit/tree/binarytree"
+)
+
+// Format prints the expression tree in infix with minimal parentheses, a
+// child is parenthesized only if it would be parsed differently without
+// parentheses. Binary operators are surrounded by spaces.
+func Format(tree *binarytree.Btree) string {
+	buffer := new(bytes.Buffer)
+	format(buffer, tree)
+	return buffer.String()
+}
+
+func operatorOf(tree *binarytree.Btree) *Operator {
+	if tree == nil {
+		return nil
+	}
+	if token, ok := tree.Element.(*Token); ok && token.Kind == OperatorToken {
+		return token.Operator
+	}
+	return nil
+}
+
+func format(buffer *bytes.Buffer, tree *binarytree.Btree) {
+	if tree == nil {
+		return
+	}
+
+	op := operatorOf(tree)
+	if op == nil {
+		buffer.WriteString(tree.Element.String())
+		return
+	}
+
+	if op.Unary {
+		buffer.WriteString(op.Symbol)
+		child := operatorOf(tree.Right)
+		formatChild(buffer, tree.Right, child != nil && !child.Unary && child.Precedence < op.Precedence)
+		return
+	}
+
+	// the left child binds looser, or as tight but the operator groups to the right
+	left := operatorOf(tree.Left)
+	formatChild(buffer, tree.Left, left != nil &&
+		(left.Precedence < op.Precedence || (left.Precedence == op.Precedence && op.RightAssoc)))
+
+	buffer.WriteString(" ")
+	buffer.WriteString(op.Symbol)
+	buffer.WriteString(" ")
+
+	// a prefix operator on the right never needs parentheses, since it binds
+	// the following operand only
+	right := operatorOf(tree.Right)
+	formatChild(buffer, tree.Right, right != nil && !right.Unary &&
+		(right.Precedence < op.Precedence || (right.Precedence == op.Precedence && !op.RightAssoc)))
+}
+
+func formatChild(buffer *bytes.Buffer, tree *binarytree.Btree, parenthesize bool) {
+	if parenthesize {
+		buffer.WriteString("(")
+	}
+	format(buffer, tree)
+	if parenthesize {
+		buffer.WriteString(")")
+	}
+}
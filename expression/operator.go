@@ -0,0 +1,210 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// Operator describe a unary prefix or a binary infix operator. Operators of
+// higher precedence bind tighter, a binary operator is left associative
+// unless RightAssoc is set.
+type Operator struct {
+	Symbol     string
+	Precedence int
+	Unary      bool
+	RightAssoc bool
+	// Apply computes the result from one operand for a unary operator, or
+	// the left and right operands for a binary operator.
+	Apply func(operands ...interface{}) (interface{}, error)
+}
+
+// Operators is an operator table, a symbol may be both a unary and a binary
+// operator such as "-". It is not safe to modify the table concurrently.
+type Operators struct {
+	unary  map[string]*Operator
+	binary map[string]*Operator
+}
+
+// NewOperators creates an empty operator table.
+func NewOperators() *Operators {
+	return &Operators{
+		unary:  make(map[string]*Operator),
+		binary: make(map[string]*Operator),
+	}
+}
+
+// Add the operator into the table, the operator with the same symbol and
+// arity is replaced. The symbol must not contain letters, digits, spaces or
+// parentheses.
+func (ops *Operators) Add(op *Operator) error {
+	if op.Symbol == "" || op.Apply == nil ||
+		strings.IndexFunc(op.Symbol, func(r rune) bool {
+			return r == '(' || r == ')' || r == '_' || r == '.' ||
+				unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r)
+		}) >= 0 {
+		return InvalidOperatorErr
+	}
+
+	if op.Unary {
+		ops.unary[op.Symbol] = op
+	} else {
+		ops.binary[op.Symbol] = op
+	}
+	return nil
+}
+
+// Unary returns the unary operator of symbol.
+func (ops *Operators) Unary(symbol string) (op *Operator, found bool) {
+	op, found = ops.unary[symbol]
+	return
+}
+
+// Binary returns the binary operator of symbol.
+func (ops *Operators) Binary(symbol string) (op *Operator, found bool) {
+	op, found = ops.binary[symbol]
+	return
+}
+
+// match returns the longest operator symbol at the beginning of s.
+func (ops *Operators) match(s string) string {
+	longest := ""
+	for _, table := range []map[string]*Operator{ops.unary, ops.binary} {
+		for symbol := range table {
+			if len(symbol) > len(longest) && strings.HasPrefix(s, symbol) {
+				longest = symbol
+			}
+		}
+	}
+	return longest
+}
+
+func numbers(operands []interface{}) ([]float64, error) {
+	values := make([]float64, len(operands))
+	for i, operand := range operands {
+		value, ok := operand.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%w: %T is not a number", OperandTypeErr, operand)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func booleans(operands []interface{}) ([]bool, error) {
+	values := make([]bool, len(operands))
+	for i, operand := range operands {
+		value, ok := operand.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: %T is not a boolean", OperandTypeErr, operand)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// NumberOperator returns an operator on float64 operands.
+func NumberOperator(symbol string, precedence int, unary, rightAssoc bool,
+	apply func(operands ...float64) (interface{}, error)) *Operator {
+	return &Operator{
+		Symbol:     symbol,
+		Precedence: precedence,
+		Unary:      unary,
+		RightAssoc: rightAssoc,
+		Apply: func(operands ...interface{}) (interface{}, error) {
+			values, err := numbers(operands)
+			if err != nil {
+				return nil, err
+			}
+			return apply(values...)
+		},
+	}
+}
+
+// BoolOperator returns an operator on bool operands.
+func BoolOperator(symbol string, precedence int, unary bool,
+	apply func(operands ...bool) (interface{}, error)) *Operator {
+	return &Operator{
+		Symbol:     symbol,
+		Precedence: precedence,
+		Unary:      unary,
+		Apply: func(operands ...interface{}) (interface{}, error) {
+			values, err := booleans(operands)
+			if err != nil {
+				return nil, err
+			}
+			return apply(values...)
+		},
+	}
+}
+
+// equal compares numbers, booleans or strings of variables.
+func equal(operands ...interface{}) (interface{}, error) {
+	switch a := operands[0].(type) {
+	case float64, bool, string:
+		if reflect.TypeOf(a) != reflect.TypeOf(operands[1]) {
+			return nil, fmt.Errorf("%w: %T and %T", OperandTypeErr, operands[0], operands[1])
+		}
+		return a == operands[1], nil
+	default:
+		return nil, fmt.Errorf("%w: %T is not comparable", OperandTypeErr, a)
+	}
+}
+
+// DefaultOperators returns a new table of the operators from the lowest
+// precedence to the highest:
+//
+//	||
+//	&&
+//	== !=
+//	< <= > >=
+//	+ -
+//	* / %
+//	unary - + !
+//	^ (right associative)
+//
+// Arithmetic and ordering operators take numbers, logical operators take
+// booleans, and == != take numbers, booleans or strings.
+func DefaultOperators() *Operators {
+	ops := NewOperators()
+	for _, op := range []*Operator{
+		BoolOperator("||", 1, false, func(x ...bool) (interface{}, error) { return x[0] || x[1], nil }),
+		BoolOperator("&&", 2, false, func(x ...bool) (interface{}, error) { return x[0] && x[1], nil }),
+		{Symbol: "==", Precedence: 3, Apply: equal},
+		{Symbol: "!=", Precedence: 3, Apply: func(operands ...interface{}) (interface{}, error) {
+			result, err := equal(operands...)
+			if err != nil {
+				return nil, err
+			}
+			return !result.(bool), nil
+		}},
+		NumberOperator("<", 4, false, false, func(x ...float64) (interface{}, error) { return x[0] < x[1], nil }),
+		NumberOperator("<=", 4, false, false, func(x ...float64) (interface{}, error) { return x[0] <= x[1], nil }),
+		NumberOperator(">", 4, false, false, func(x ...float64) (interface{}, error) { return x[0] > x[1], nil }),
+		NumberOperator(">=", 4, false, false, func(x ...float64) (interface{}, error) { return x[0] >= x[1], nil }),
+		NumberOperator("+", 5, false, false, func(x ...float64) (interface{}, error) { return x[0] + x[1], nil }),
+		NumberOperator("-", 5, false, false, func(x ...float64) (interface{}, error) { return x[0] - x[1], nil }),
+		NumberOperator("*", 6, false, false, func(x ...float64) (interface{}, error) { return x[0] * x[1], nil }),
+		NumberOperator("/", 6, false, false, func(x ...float64) (interface{}, error) {
+			if x[1] == 0 {
+				return nil, DivisionByZeroErr
+			}
+			return x[0] / x[1], nil
+		}),
+		NumberOperator("%", 6, false, false, func(x ...float64) (interface{}, error) {
+			if x[1] == 0 {
+				return nil, DivisionByZeroErr
+			}
+			return math.Mod(x[0], x[1]), nil
+		}),
+		NumberOperator("-", 7, true, false, func(x ...float64) (interface{}, error) { return -x[0], nil }),
+		NumberOperator("+", 7, true, false, func(x ...float64) (interface{}, error) { return x[0], nil }),
+		BoolOperator("!", 7, true, func(x ...bool) (interface{}, error) { return !x[0], nil }),
+		NumberOperator("^", 8, false, true, func(x ...float64) (interface{}, error) { return math.Pow(x[0], x[1]), nil }),
+	} {
+		ops.Add(op)
+	}
+	return ops
+}
@@ -0,0 +1,145 @@
+package expression
+
+import (
+	"github.com/aiden0z/kit/stack"
+	"github.com/aiden0z/kit/tree/binarytree"
+)
+
+// ToPostfix converts the infix expression to postfix tokens by the
+// shunting-yard algorithm. A symbol is a unary operator where an operand is
+// expected, or a binary operator otherwise.
+func ToPostfix(expr string, ops *Operators) ([]*Token, error) {
+	tokens, err := Tokenize(expr, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]*Token, 0, len(tokens))
+	operators := stack.NewStack(uint(len(tokens)))
+	expectOperand := true
+
+	for _, token := range tokens {
+		switch token.Kind {
+		case NumberToken, IdentifierToken:
+			if !expectOperand {
+				return nil, errorAt(token.Pos, UnexpectedTokenErr)
+			}
+			output = append(output, token)
+			expectOperand = false
+
+		case LeftParenToken:
+			if !expectOperand {
+				return nil, errorAt(token.Pos, UnexpectedTokenErr)
+			}
+			operators.Push(token)
+
+		case RightParenToken:
+			if expectOperand {
+				return nil, errorAt(token.Pos, MissingOperandErr)
+			}
+			for {
+				top, ok := operators.Pop().(*Token)
+				if !ok {
+					return nil, errorAt(token.Pos, MismatchedParenthesisErr)
+				}
+				if top.Kind == LeftParenToken {
+					break
+				}
+				output = append(output, top)
+			}
+
+		case OperatorToken:
+			if expectOperand {
+				op, found := ops.Unary(token.Text)
+				if !found {
+					return nil, errorAt(token.Pos, MissingOperandErr)
+				}
+				// a prefix operator applies to the following operand, nothing
+				// on the stack can be popped
+				token.Operator = op
+				operators.Push(token)
+				continue
+			}
+
+			op, found := ops.Binary(token.Text)
+			if !found {
+				return nil, errorAt(token.Pos, UnexpectedTokenErr)
+			}
+			token.Operator = op
+			for {
+				top, ok := operators.Peek().(*Token)
+				if !ok || top.Kind == LeftParenToken {
+					break
+				}
+				if top.Operator.Precedence < op.Precedence ||
+					(top.Operator.Precedence == op.Precedence && op.RightAssoc) {
+					break
+				}
+				output = append(output, operators.Pop().(*Token))
+			}
+			operators.Push(token)
+			expectOperand = true
+		}
+	}
+
+	if expectOperand {
+		return nil, errorAt(len(expr), MissingOperandErr)
+	}
+	for !operators.IsEmpty() {
+		top := operators.Pop().(*Token)
+		if top.Kind == LeftParenToken {
+			return nil, errorAt(top.Pos, MismatchedParenthesisErr)
+		}
+		output = append(output, top)
+	}
+	return output, nil
+}
+
+// BuildTree builds the expression tree from postfix tokens. Operand tokens
+// are leaves, a binary operator node has both children, and a unary operator
+// node has the right child only.
+func BuildTree(postfix []*Token) (*binarytree.Btree, error) {
+	operands := stack.NewStack(uint(len(postfix)))
+
+	for _, token := range postfix {
+		node := &binarytree.Btree{Element: token}
+		switch {
+		case token.Kind == NumberToken || token.Kind == IdentifierToken:
+		case token.Kind == OperatorToken && token.Operator != nil && token.Operator.Unary:
+			if operands.IsEmpty() {
+				return nil, errorAt(token.Pos, MissingOperandErr)
+			}
+			node.Right = operands.Pop().(*binarytree.Btree)
+		case token.Kind == OperatorToken && token.Operator != nil:
+			if operands.Len() < 2 {
+				return nil, errorAt(token.Pos, MissingOperandErr)
+			}
+			node.Right = operands.Pop().(*binarytree.Btree)
+			node.Left = operands.Pop().(*binarytree.Btree)
+		default:
+			return nil, errorAt(token.Pos, UnexpectedTokenErr)
+		}
+		operands.Push(node)
+	}
+
+	switch operands.Len() {
+	case 0:
+		return nil, errorAt(0, MissingOperandErr)
+	case 1:
+		return operands.Pop().(*binarytree.Btree), nil
+	default:
+		// the operand below the top has no operator
+		operands.Pop()
+		extra := operands.Pop().(*binarytree.Btree)
+		return nil, errorAt(extra.Element.(*Token).Pos, UnexpectedTokenErr)
+	}
+}
+
+// Parse parses the infix expression into an expression tree.
+func Parse(expr string, ops *Operators) (*binarytree.Btree, error) {
+	postfix, err := ToPostfix(expr, ops)
+	if err != nil {
+		return nil, err
+	}
+	return BuildTree(postfix)
+}
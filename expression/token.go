@@ -0,0 +1,133 @@
+package expression
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/aiden0z/kit/base"
+)
+
+// TokenKind is the kind of Token.
+type TokenKind int
+
+const (
+	NumberToken TokenKind = iota
+	IdentifierToken
+	OperatorToken
+	LeftParenToken
+	RightParenToken
+)
+
+// Token describe a token of expression, it is the element of the expression
+// tree nodes.
+type Token struct {
+	Kind TokenKind
+	Text string
+	// Pos is the byte offset of the token in the expression
+	Pos int
+	// Value is the value of a NumberToken
+	Value float64
+	// Operator is the operator of an OperatorToken, it is resolved by parsing
+	// since a symbol may be both a unary and a binary operator
+	Operator *Operator
+}
+
+// CompareTo compares the token text.
+func (t *Token) CompareTo(o base.Comparable) int {
+	other, ok := o.(*Token)
+	if !ok {
+		return 1
+	}
+	return strings.Compare(t.Text, other.Text)
+}
+
+func (t *Token) String() string {
+	return t.Text
+}
+
+func isIdentifierStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentifierPart(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Tokenize splits the expression into tokens, operator symbols are matched
+// by the longest symbol of ops.
+func Tokenize(expr string, ops *Operators) ([]*Token, error) {
+	tokens := []*Token{}
+	for pos := 0; pos < len(expr); {
+		r, size := utf8.DecodeRuneInString(expr[pos:])
+		switch {
+		case unicode.IsSpace(r):
+			pos += size
+
+		case r == '(':
+			tokens = append(tokens, &Token{Kind: LeftParenToken, Text: "(", Pos: pos})
+			pos++
+
+		case r == ')':
+			tokens = append(tokens, &Token{Kind: RightParenToken, Text: ")", Pos: pos})
+			pos++
+
+		case r == '.' || (r >= '0' && r <= '9'):
+			end := scanNumber(expr, pos)
+			value, err := strconv.ParseFloat(expr[pos:end], 64)
+			if err != nil {
+				return nil, errorAt(pos, UnexpectedTokenErr)
+			}
+			tokens = append(tokens, &Token{Kind: NumberToken, Text: expr[pos:end], Pos: pos, Value: value})
+			pos = end
+
+		case isIdentifierStart(r):
+			end := pos + size
+			for end < len(expr) {
+				r, size := utf8.DecodeRuneInString(expr[end:])
+				if !isIdentifierPart(r) {
+					break
+				}
+				end += size
+			}
+			tokens = append(tokens, &Token{Kind: IdentifierToken, Text: expr[pos:end], Pos: pos})
+			pos = end
+
+		default:
+			symbol := ops.match(expr[pos:])
+			if symbol == "" {
+				return nil, errorAt(pos, UnexpectedTokenErr)
+			}
+			tokens = append(tokens, &Token{Kind: OperatorToken, Text: symbol, Pos: pos})
+			pos += len(symbol)
+		}
+	}
+	return tokens, nil
+}
+
+// scanNumber returns the end of the number starts at pos, digits with an
+// optional fraction and exponent.
+func scanNumber(expr string, pos int) int {
+	digits := func(i int) int {
+		for i < len(expr) && expr[i] >= '0' && expr[i] <= '9' {
+			i++
+		}
+		return i
+	}
+
+	end := digits(pos)
+	if end < len(expr) && expr[end] == '.' {
+		end = digits(end + 1)
+	}
+	if end < len(expr) && (expr[end] == 'e' || expr[end] == 'E') {
+		i := end + 1
+		if i < len(expr) && (expr[i] == '+' || expr[i] == '-') {
+			i++
+		}
+		if j := digits(i); j > i {
+			end = j
+		}
+	}
+	return end
+}
@@ -1,3 +1,5 @@
 module github.com/aiden0z/kit
 
 go 1.13
+
+require golang.org/x/text v0.3.3
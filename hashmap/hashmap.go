@@ -0,0 +1,249 @@
+// Package hashmap implements a hash map and a hash set over base.Hashable keys.
+// The map uses open addressing with linear probing, the hash code of every
+// key is cached in its slot, and deletion shifts the following entries back
+// instead of leaving tombstones, so lookups never degrade after deletions.
+// The table grows when the load factor exceeds the maximum load factor.
+package hashmap
+
+import (
+	"errors"
+
+	"github.com/aiden0z/kit/base"
+)
+
+const (
+	// DefaultMaxLoadFactor is the maximum load factor used by NewHashMap.
+	DefaultMaxLoadFactor = 0.75
+	minCapacity          = 8
+)
+
+// InvalidLoadFactorErr is returned when the max load factor is not in (0, 1).
+var InvalidLoadFactorErr = errors.New("load factor must be in (0, 1)")
+
+// HashMap describe a hash map, it is not safe for concurrent usage.
+type HashMap struct {
+	slots         []slot
+	size          int
+	maxLoadFactor float64
+}
+
+type slot struct {
+	used  bool
+	hash  uint64
+	key   base.Hashable
+	value interface{}
+}
+
+// NewHashMap creates an empty HashMap.
+func NewHashMap() *HashMap {
+	hashMap, _ := NewHashMapWithLoadFactor(0, DefaultMaxLoadFactor)
+	return hashMap
+}
+
+// NewHashMapWithLoadFactor creates a HashMap with room for capacity entries
+// without growing, and the table grows when its load factor exceeds maxLoadFactor.
+func NewHashMapWithLoadFactor(capacity int, maxLoadFactor float64) (*HashMap, error) {
+	if maxLoadFactor <= 0 || maxLoadFactor >= 1 {
+		return nil, InvalidLoadFactorErr
+	}
+
+	hashMap := &HashMap{maxLoadFactor: maxLoadFactor}
+	hashMap.slots = make([]slot, hashMap.tableSize(capacity))
+	return hashMap, nil
+}
+
+// tableSize return the smallest power of two table size holds n entries.
+func (m *HashMap) tableSize(n int) int {
+	size := minCapacity
+	for float64(n) > float64(size)*m.maxLoadFactor {
+		size *= 2
+	}
+	return size
+}
+
+func (m *HashMap) mask() int {
+	return len(m.slots) - 1
+}
+
+// find return the slot index of key, or the empty slot where key should be
+// inserted and false.
+func (m *HashMap) find(key base.Hashable, hash uint64) (index int, found bool) {
+	mask := m.mask()
+	for i := int(hash) & mask; ; i = (i + 1) & mask {
+		s := &m.slots[i]
+		if !s.used {
+			return i, false
+		}
+		if s.hash == hash && s.key.EqualTo(key) {
+			return i, true
+		}
+	}
+}
+
+func (m *HashMap) resize(size int) {
+	slots := m.slots
+	m.slots = make([]slot, size)
+	mask := m.mask()
+
+	for _, s := range slots {
+		if !s.used {
+			continue
+		}
+		i := int(s.hash) & mask
+		for m.slots[i].used {
+			i = (i + 1) & mask
+		}
+		m.slots[i] = s
+	}
+}
+
+// Put the key, value entry, return true if the key is new or false if the
+// value of an exist key is updated.
+func (m *HashMap) Put(key base.Hashable, value interface{}) (inserted bool) {
+	hash := key.Hash()
+	index, found := m.find(key, hash)
+	if found {
+		m.slots[index].value = value
+		return false
+	}
+
+	if float64(m.size+1) > float64(len(m.slots))*m.maxLoadFactor {
+		m.resize(len(m.slots) * 2)
+		index, _ = m.find(key, hash)
+	}
+
+	m.slots[index] = slot{used: true, hash: hash, key: key, value: value}
+	m.size++
+	return true
+}
+
+// Get returns the value of key.
+func (m *HashMap) Get(key base.Hashable) (value interface{}, found bool) {
+	index, found := m.find(key, key.Hash())
+	if !found {
+		return nil, false
+	}
+	return m.slots[index].value, true
+}
+
+// Contains return true if key exists.
+func (m *HashMap) Contains(key base.Hashable) bool {
+	_, found := m.find(key, key.Hash())
+	return found
+}
+
+// Remove the key, return true if found.
+func (m *HashMap) Remove(key base.Hashable) bool {
+	index, found := m.find(key, key.Hash())
+	if !found {
+		return false
+	}
+
+	// backward shift the following entries of the probe sequence
+	mask := m.mask()
+	for next := (index + 1) & mask; m.slots[next].used; next = (next + 1) & mask {
+		ideal := int(m.slots[next].hash) & mask
+		// the entry at next can be moved to index only if index is on its
+		// probe sequence, from ideal to next cyclically
+		if (next > index && (ideal <= index || ideal > next)) ||
+			(next < index && (ideal <= index && ideal > next)) {
+			m.slots[index] = m.slots[next]
+			index = next
+		}
+	}
+
+	m.slots[index] = slot{}
+	m.size--
+	return true
+}
+
+// Size returns the number of entries.
+func (m *HashMap) Size() int {
+	return m.size
+}
+
+// IsEmpty checks if the map is empty.
+func (m *HashMap) IsEmpty() bool {
+	return m.size == 0
+}
+
+// Capacity returns the number of slots in the table.
+func (m *HashMap) Capacity() int {
+	return len(m.slots)
+}
+
+// LoadFactor returns the current load factor, size / capacity.
+func (m *HashMap) LoadFactor() float64 {
+	return float64(m.size) / float64(len(m.slots))
+}
+
+// MaxLoadFactor returns the load factor which the table grows above.
+func (m *HashMap) MaxLoadFactor() float64 {
+	return m.maxLoadFactor
+}
+
+// SetMaxLoadFactor changes the max load factor, the table grows immediately
+// if the current load factor exceeds it.
+func (m *HashMap) SetMaxLoadFactor(maxLoadFactor float64) error {
+	if maxLoadFactor <= 0 || maxLoadFactor >= 1 {
+		return InvalidLoadFactorErr
+	}
+
+	m.maxLoadFactor = maxLoadFactor
+	if size := m.tableSize(m.size); size > len(m.slots) {
+		m.resize(size)
+	}
+	return nil
+}
+
+// Reserve grows the table so that n entries fit without growing again.
+func (m *HashMap) Reserve(n int) {
+	if size := m.tableSize(n); size > len(m.slots) {
+		m.resize(size)
+	}
+}
+
+// Shrink shrinks the table to the smallest size holds the current entries.
+func (m *HashMap) Shrink() {
+	if size := m.tableSize(m.size); size < len(m.slots) {
+		m.resize(size)
+	}
+}
+
+// Clear removes all entries and keeps the capacity.
+func (m *HashMap) Clear() {
+	for i := range m.slots {
+		m.slots[i] = slot{}
+	}
+	m.size = 0
+}
+
+// Range calls fn for every entry in unspecified order until fn returns false.
+// The map must not be modified during iteration.
+func (m *HashMap) Range(fn func(key base.Hashable, value interface{}) bool) {
+	for _, s := range m.slots {
+		if s.used && !fn(s.key, s.value) {
+			return
+		}
+	}
+}
+
+// Keys returns all keys in unspecified order.
+func (m *HashMap) Keys() []base.Hashable {
+	keys := make([]base.Hashable, 0, m.size)
+	m.Range(func(key base.Hashable, value interface{}) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Values returns all values in unspecified order.
+func (m *HashMap) Values() []interface{} {
+	values := make([]interface{}, 0, m.size)
+	m.Range(func(key base.Hashable, value interface{}) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
@@ -0,0 +1,172 @@
+package hashmap
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/aiden0z/kit/base"
+)
+
+// collision is a Hashable with a constant hash code to exercise probing.
+type collision int
+
+func (c collision) Hash() uint64 {
+	return 42
+}
+
+func (c collision) EqualTo(o base.Hashable) bool {
+	other, ok := o.(collision)
+	return ok && c == other
+}
+
+func TestHashMapPutGet(t *testing.T) {
+	m := NewHashMap()
+
+	for i := 0; i < 100; i++ {
+		if !m.Put(base.Int(i), i) {
+			t.Errorf("Put new key %d return false", i)
+		}
+	}
+	if m.Put(base.Int(10), "ten") {
+		t.Error("Put exist key return true")
+	}
+
+	if m.Size() != 100 {
+		t.Errorf("Got %v expected %v for map size", m.Size(), 100)
+	}
+
+	if value, found := m.Get(base.Int(10)); !found || value != "ten" {
+		t.Errorf("Got %v, %v expected %v, %v", value, found, "ten", true)
+	}
+	if value, found := m.Get(base.Int(99)); !found || value != 99 {
+		t.Errorf("Got %v, %v expected %v, %v", value, found, 99, true)
+	}
+	if _, found := m.Get(base.Int(100)); found {
+		t.Error("Get a non exist key")
+	}
+	if m.Contains(base.Rune(10)) {
+		t.Error("Contains a key of different type")
+	}
+
+	if m.LoadFactor() > m.MaxLoadFactor() {
+		t.Errorf("Load factor %v exceeds max load factor %v", m.LoadFactor(), m.MaxLoadFactor())
+	}
+}
+
+func TestHashMapRemove(t *testing.T) {
+	m := NewHashMap()
+	for i := 0; i < 6; i++ {
+		m.Put(collision(i), i)
+	}
+
+	if m.Remove(collision(10)) {
+		t.Error("Remove non exist key return true")
+	}
+	if !m.Remove(collision(2)) {
+		t.Error("Remove exist key return false")
+	}
+
+	for i := 0; i < 6; i++ {
+		if _, found := m.Get(collision(i)); found != (i != 2) {
+			t.Errorf("Got %v for key %d after remove", found, i)
+		}
+	}
+}
+
+func TestHashMapRandom(t *testing.T) {
+	m := NewHashMap()
+	expected := make(map[int]int)
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 10000; i++ {
+		key := r.Intn(500)
+		if r.Intn(3) == 0 {
+			_, exist := expected[key]
+			if m.Remove(base.Int(key)) != exist {
+				t.Errorf("Remove key %d return %v", key, !exist)
+			}
+			delete(expected, key)
+		} else {
+			m.Put(base.Int(key), i)
+			expected[key] = i
+		}
+	}
+
+	if m.Size() != len(expected) {
+		t.Errorf("Got %v expected %v for map size", m.Size(), len(expected))
+	}
+	for key, value := range expected {
+		if v, found := m.Get(base.Int(key)); !found || v != value {
+			t.Errorf("Got %v, %v expected %v for key %d", v, found, value, key)
+		}
+	}
+
+	count := 0
+	m.Range(func(key base.Hashable, value interface{}) bool {
+		if expected[int(key.(base.Int))] != value {
+			t.Errorf("Range got %v for key %v", value, key)
+		}
+		count++
+		return true
+	})
+	if count != len(expected) || len(m.Keys()) != len(expected) || len(m.Values()) != len(expected) {
+		t.Errorf("Range visited %d entries, expected %d", count, len(expected))
+	}
+}
+
+func TestHashMapLoadFactor(t *testing.T) {
+	if _, err := NewHashMapWithLoadFactor(0, 1); err != InvalidLoadFactorErr {
+		t.Errorf("Got %v expected %v", err, InvalidLoadFactorErr)
+	}
+
+	m, err := NewHashMapWithLoadFactor(100, 0.5)
+	if err != nil {
+		t.Errorf("Got %v expected nil", err)
+	}
+	capacity := m.Capacity()
+	if capacity < 200 {
+		t.Errorf("Got %v capacity for 100 entries with load factor 0.5", capacity)
+	}
+
+	for i := 0; i < 100; i++ {
+		m.Put(base.Int(i), nil)
+	}
+	if m.Capacity() != capacity {
+		t.Error("Map grows before reaching the reserved capacity")
+	}
+
+	m.SetMaxLoadFactor(0.25)
+	if m.LoadFactor() > 0.25 {
+		t.Errorf("Load factor %v exceeds max load factor after SetMaxLoadFactor", m.LoadFactor())
+	}
+
+	for i := 0; i < 90; i++ {
+		m.Remove(base.Int(i))
+	}
+	m.Shrink()
+	if m.Capacity() >= capacity || m.Size() != 10 {
+		t.Errorf("Got %v capacity after shrink", m.Capacity())
+	}
+	for i := 90; i < 100; i++ {
+		if !m.Contains(base.Int(i)) {
+			t.Errorf("Lost key %d after shrink", i)
+		}
+	}
+
+	m.Reserve(1000)
+	if float64(1000) > float64(m.Capacity())*m.MaxLoadFactor() {
+		t.Errorf("Got %v capacity after reserve", m.Capacity())
+	}
+
+	m.Clear()
+	if !m.IsEmpty() || m.Contains(base.Int(95)) {
+		t.Error("Clear map error")
+	}
+}
+
+func BenchmarkHashMapPut(b *testing.B) {
+	m := NewHashMap()
+	for i := 0; i < b.N; i++ {
+		m.Put(base.Int(i), i)
+	}
+}
@@ -0,0 +1,94 @@
+package hashmap
+
+import (
+	"github.com/aiden0z/kit/base"
+)
+
+// HashSet describe a hash set based on HashMap, it is not safe for concurrent usage.
+type HashSet struct {
+	m *HashMap
+}
+
+// NewHashSet creates an empty HashSet.
+func NewHashSet() *HashSet {
+	return &HashSet{m: NewHashMap()}
+}
+
+// NewHashSetWithLoadFactor creates a HashSet with room for capacity items
+// without growing, and the table grows when its load factor exceeds maxLoadFactor.
+func NewHashSetWithLoadFactor(capacity int, maxLoadFactor float64) (*HashSet, error) {
+	m, err := NewHashMapWithLoadFactor(capacity, maxLoadFactor)
+	if err != nil {
+		return nil, err
+	}
+	return &HashSet{m: m}, nil
+}
+
+// Add the item, return true if the item is new.
+func (s *HashSet) Add(item base.Hashable) bool {
+	return s.m.Put(item, nil)
+}
+
+// Remove the item, return true if found.
+func (s *HashSet) Remove(item base.Hashable) bool {
+	return s.m.Remove(item)
+}
+
+// Contains return true if item exists.
+func (s *HashSet) Contains(item base.Hashable) bool {
+	return s.m.Contains(item)
+}
+
+// Size returns the number of items.
+func (s *HashSet) Size() int {
+	return s.m.Size()
+}
+
+// IsEmpty checks if the set is empty.
+func (s *HashSet) IsEmpty() bool {
+	return s.m.IsEmpty()
+}
+
+// Capacity returns the number of slots in the table.
+func (s *HashSet) Capacity() int {
+	return s.m.Capacity()
+}
+
+// LoadFactor returns the current load factor, size / capacity.
+func (s *HashSet) LoadFactor() float64 {
+	return s.m.LoadFactor()
+}
+
+// SetMaxLoadFactor changes the max load factor, the table grows immediately
+// if the current load factor exceeds it.
+func (s *HashSet) SetMaxLoadFactor(maxLoadFactor float64) error {
+	return s.m.SetMaxLoadFactor(maxLoadFactor)
+}
+
+// Reserve grows the table so that n items fit without growing again.
+func (s *HashSet) Reserve(n int) {
+	s.m.Reserve(n)
+}
+
+// Shrink shrinks the table to the smallest size holds the current items.
+func (s *HashSet) Shrink() {
+	s.m.Shrink()
+}
+
+// Clear removes all items and keeps the capacity.
+func (s *HashSet) Clear() {
+	s.m.Clear()
+}
+
+// Range calls fn for every item in unspecified order until fn returns false.
+// The set must not be modified during iteration.
+func (s *HashSet) Range(fn func(item base.Hashable) bool) {
+	s.m.Range(func(key base.Hashable, value interface{}) bool {
+		return fn(key)
+	})
+}
+
+// Values returns all items in unspecified order.
+func (s *HashSet) Values() []base.Hashable {
+	return s.m.Keys()
+}
@@ -0,0 +1,54 @@
+package hashmap
+
+import (
+	"testing"
+
+	"github.com/aiden0z/kit/base"
+)
+
+func TestHashSet(t *testing.T) {
+	s := NewHashSet()
+
+	for _, item := range []base.Hashable{base.String("a"), base.String("b"), base.Int(1), base.NewTuple(base.String("a"), base.Int(1))} {
+		if !s.Add(item) {
+			t.Errorf("Add new item %v return false", item)
+		}
+	}
+
+	if s.Add(base.String("a")) || s.Add(base.NewTuple(base.String("a"), base.Int(1))) {
+		t.Error("Add exist item return true")
+	}
+	if s.Size() != 4 {
+		t.Errorf("Got %v expected %v for set size", s.Size(), 4)
+	}
+
+	if !s.Contains(base.Int(1)) || s.Contains(base.Int64(1)) {
+		t.Error("Contains work error")
+	}
+
+	if !s.Remove(base.String("b")) || s.Remove(base.String("b")) {
+		t.Error("Remove work error")
+	}
+
+	count := 0
+	s.Range(func(item base.Hashable) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t.Errorf("Range not stop, visited %d items", count)
+	}
+
+	if len(s.Values()) != 3 {
+		t.Errorf("Got %v expected %v for values", len(s.Values()), 3)
+	}
+
+	if _, err := NewHashSetWithLoadFactor(10, 0); err != InvalidLoadFactorErr {
+		t.Errorf("Got %v expected %v", err, InvalidLoadFactorErr)
+	}
+
+	s.Clear()
+	if !s.IsEmpty() {
+		t.Error("Clear set error")
+	}
+}
@@ -0,0 +1,133 @@
+package queue
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Channel adapts a Deque to a pair of channels, items sent to In are
+// buffered in the deque and received from Out in FIFO order. With an
+// unlimited deque the channel is unbounded, senders never block.
+//
+//	In --> [ deque ] --> Out
+//
+// Close stops accepting items, the buffered items are still delivered
+// through Out, which is closed after the last one.
+type Channel struct {
+	in  chan interface{}
+	out chan interface{}
+	// stop is closed by Close, In is left for the callers to close
+	stop   chan struct{}
+	buffer *Deque
+	// pending is 1 while an item received from In is held back because
+	// the deque is full
+	pending   int32
+	closeOnce sync.Once
+}
+
+// NewChannel creates an unbounded Channel.
+func NewChannel() *Channel {
+	return NewChannelWithDeque(NewDeque())
+}
+
+// NewChannelWithDeque creates a Channel buffered in deque, the items already
+// in the deque are received first. Pass q.Deque to adapt a Queue.
+//
+// A capped deque bounds the channel: when it is full, the DropOldest and
+// DropNewest policies drop items and the other policies block the senders
+// until Out is received. The deque must not be modified by others until Out
+// is closed.
+func NewChannelWithDeque(deque *Deque) *Channel {
+	c := &Channel{
+		in:     make(chan interface{}),
+		out:    make(chan interface{}),
+		stop:   make(chan struct{}),
+		buffer: deque,
+	}
+	go c.run()
+	return c
+}
+
+// In returns the channel to send items to. Callers may close it when they
+// are done sending, which is the same as Close, and Close may still be
+// called after it.
+func (c *Channel) In() chan<- interface{} {
+	return c.in
+}
+
+// Out returns the channel to receive items from, it is closed after Close
+// and the buffered items are received.
+func (c *Channel) Out() <-chan interface{} {
+	return c.out
+}
+
+// Len returns the number of items sent but not received yet.
+func (c *Channel) Len() int {
+	return c.buffer.Size() + int(atomic.LoadInt32(&c.pending))
+}
+
+// Close stops receiving items from In, it is safe to call more than once,
+// also after In is closed. Close does not close In, an item sent after Close
+// is never received and blocks the sender, so the senders must stop first.
+func (c *Channel) Close() {
+	c.closeOnce.Do(func() {
+		close(c.stop)
+	})
+}
+
+// offer buffers item, return false if the deque is full and the item must
+// be held back.
+func (c *Channel) offer(item interface{}) bool {
+	if c.buffer.Policy() == BlockOverflow && c.buffer.IsFull() {
+		return false
+	}
+	return c.buffer.Append(item) == nil
+}
+
+func (c *Channel) run() {
+	in, stop := c.in, c.stop
+	var held interface{}
+
+	for {
+		var out chan interface{}
+		var next interface{}
+		fromBuffer := !c.buffer.IsEmpty()
+		if fromBuffer {
+			out, next = c.out, c.buffer.First()
+		} else if atomic.LoadInt32(&c.pending) == 1 {
+			out, next = c.out, held
+		} else if in == nil {
+			close(c.out)
+			return
+		}
+
+		// stop receiving while an item is held back
+		receive := in
+		if atomic.LoadInt32(&c.pending) == 1 {
+			receive = nil
+		}
+
+		select {
+		case item, ok := <-receive:
+			if !ok {
+				in = nil
+			} else if !c.offer(item) {
+				held = item
+				atomic.StoreInt32(&c.pending, 1)
+			}
+		case <-stop:
+			in, stop = nil, nil
+		case out <- next:
+			if fromBuffer {
+				c.buffer.Shift()
+				if atomic.LoadInt32(&c.pending) == 1 && c.offer(held) {
+					held = nil
+					atomic.StoreInt32(&c.pending, 0)
+				}
+			} else {
+				held = nil
+				atomic.StoreInt32(&c.pending, 0)
+			}
+		}
+	}
+}
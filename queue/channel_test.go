@@ -0,0 +1,182 @@
+package queue
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestChannelFIFO(t *testing.T) {
+	c := NewChannel()
+	sampleSize := 1000
+
+	// the channel is unbounded, sending never blocks
+	for i := 0; i < sampleSize; i++ {
+		c.In() <- i
+	}
+	c.Close()
+	c.Close()
+
+	expected := 0
+	for item := range c.Out() {
+		if item != expected {
+			t.Fatalf("Got %v expected %v for received item", item, expected)
+		}
+		expected++
+	}
+	if expected != sampleSize || c.Len() != 0 {
+		t.Errorf("Got %v expected %v for received items", expected, sampleSize)
+	}
+}
+
+func TestChannelCloseIn(t *testing.T) {
+	c := NewChannel()
+	c.In() <- 1
+	close(c.In())
+	c.Close()
+	c.Close()
+
+	count := 0
+	for item := range c.Out() {
+		if item != 1 {
+			t.Errorf("Got %v expected %v for received item", item, 1)
+		}
+		count++
+	}
+	if count != 1 {
+		t.Errorf("Got %v expected %v for received items", count, 1)
+	}
+}
+
+func TestChannelConcurrent(t *testing.T) {
+	c := NewChannel()
+	producers, sampleSize := 4, 1000
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < sampleSize; i++ {
+				c.In() <- [2]int{p, i}
+			}
+		}(p)
+	}
+	go func() {
+		wg.Wait()
+		c.Close()
+	}()
+
+	// items of every producer are received in the order sent
+	next := make([]int, producers)
+	for item := range c.Out() {
+		pair := item.([2]int)
+		if pair[1] != next[pair[0]] {
+			t.Fatalf("Got %v expected %v for producer %v", pair[1], next[pair[0]], pair[0])
+		}
+		next[pair[0]]++
+	}
+	for p := range next {
+		if next[p] != sampleSize {
+			t.Errorf("Got %v expected %v items of producer %v", next[p], sampleSize, p)
+		}
+	}
+}
+
+func TestChannelWithDeque(t *testing.T) {
+	q := NewQueue()
+	q.Enqueue(0)
+	q.Enqueue(1)
+
+	c := NewChannelWithDeque(q.Deque)
+	c.In() <- 2
+	close(c.In())
+
+	expected := 0
+	for item := range c.Out() {
+		if item != expected {
+			t.Errorf("Got %v expected %v for received item", item, expected)
+		}
+		expected++
+	}
+	if expected != 3 {
+		t.Errorf("Got %v expected %v for received items", expected, 3)
+	}
+}
+
+func TestChannelBounded(t *testing.T) {
+	for _, policy := range []OverflowPolicy{RejectOverflow, BlockOverflow} {
+		c := NewChannelWithDeque(NewCappedDequeWithPolicy(2, policy))
+		c.In() <- 0
+		c.In() <- 1
+		// held back until there is room in the deque
+		c.In() <- 2
+
+		select {
+		case c.In() <- 3:
+			t.Fatalf("Send to full channel not block for policy %v", policy)
+		case <-time.After(10 * time.Millisecond):
+		}
+		if c.Len() != 3 {
+			t.Errorf("Got %v expected %v for channel length", c.Len(), 3)
+		}
+
+		if item := <-c.Out(); item != 0 {
+			t.Errorf("Got %v expected %v for received item", item, 0)
+		}
+		c.In() <- 3
+		c.Close()
+
+		expected := 1
+		for item := range c.Out() {
+			if item != expected {
+				t.Errorf("Got %v expected %v for received item", item, expected)
+			}
+			expected++
+		}
+		if expected != 4 {
+			t.Errorf("Got %v expected %v for received items", expected, 4)
+		}
+	}
+}
+
+func TestChannelUnbuffered(t *testing.T) {
+	c := NewChannelWithDeque(NewCappedDeque(0))
+	go func() {
+		for i := 0; i < 10; i++ {
+			c.In() <- i
+		}
+		c.Close()
+	}()
+
+	expected := 0
+	for item := range c.Out() {
+		if item != expected {
+			t.Errorf("Got %v expected %v for received item", item, expected)
+		}
+		expected++
+	}
+	if expected != 10 {
+		t.Errorf("Got %v expected %v for received items", expected, 10)
+	}
+}
+
+func TestChannelDropOldest(t *testing.T) {
+	deque := NewCappedDequeWithPolicy(2, DropOldest)
+	c := NewChannelWithDeque(deque)
+	for i := 0; i < 5; i++ {
+		c.In() <- i
+	}
+	c.Close()
+
+	expected := 3
+	for item := range c.Out() {
+		if item != expected {
+			t.Errorf("Got %v expected %v for received item", item, expected)
+		}
+		expected++
+	}
+	if expected != 5 || deque.Dropped() != 3 {
+		t.Errorf("Got %v expected %v for dropped items", deque.Dropped(), 3)
+	}
+}
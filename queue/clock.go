@@ -0,0 +1,137 @@
+package queue
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the time source of DelayQueue, TimingWheel and ReliableQueue,
+// tests inject a ManualClock to run deterministically without sleeping.
+type Clock interface {
+	Now() time.Time
+	// NewTimer creates a Timer sends the current time on its channel after
+	// at least duration d.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a single event timer created by Clock.
+type Timer interface {
+	C() <-chan time.Time
+	// Stop prevents the Timer from firing, it returns false if the timer has
+	// already fired or been stopped.
+	Stop() bool
+}
+
+type systemClock struct{}
+
+type systemTimer struct {
+	*time.Timer
+}
+
+// SystemClock is the Clock of the time package.
+var SystemClock Clock = systemClock{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+// ManualClock is a Clock which only moves forward by Advance, so that timers
+// fire deterministically. It is safe for concurrent usage.
+type ManualClock struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers map[*manualTimer]struct{}
+}
+
+type manualTimer struct {
+	clock    *ManualClock
+	deadline time.Time
+	c        chan time.Time
+}
+
+// NewManualClock creates a ManualClock starts at now.
+func NewManualClock(now time.Time) *ManualClock {
+	clock := &ManualClock{now: now, timers: make(map[*manualTimer]struct{})}
+	clock.cond = sync.NewCond(&clock.mu)
+	return clock
+}
+
+func (clock *ManualClock) Now() time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	return clock.now
+}
+
+func (clock *ManualClock) NewTimer(d time.Duration) Timer {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	timer := &manualTimer{clock: clock, deadline: clock.now.Add(d), c: make(chan time.Time, 1)}
+	if d <= 0 {
+		timer.c <- clock.now
+		return timer
+	}
+	clock.timers[timer] = struct{}{}
+	clock.cond.Broadcast()
+	return timer
+}
+
+// Advance moves the clock forward by d and fires the due timers.
+func (clock *ManualClock) Advance(d time.Duration) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	clock.now = clock.now.Add(d)
+	for timer := range clock.timers {
+		if !timer.deadline.After(clock.now) {
+			timer.c <- clock.now
+			delete(clock.timers, timer)
+		}
+	}
+	clock.cond.Broadcast()
+}
+
+// Timers returns the number of pending timers.
+func (clock *ManualClock) Timers() int {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	return len(clock.timers)
+}
+
+// BlockUntil blocks until there are at least n pending timers, tests call it
+// to wait for a goroutine to start waiting before Advance.
+func (clock *ManualClock) BlockUntil(n int) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	for len(clock.timers) < n {
+		clock.cond.Wait()
+	}
+}
+
+func (t *manualTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *manualTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	if _, ok := t.clock.timers[t]; !ok {
+		return false
+	}
+	delete(t.clock.timers, t)
+	t.clock.cond.Broadcast()
+	return true
+}
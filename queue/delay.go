@@ -0,0 +1,160 @@
+package queue
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// DelayQueue is a queue of items which become available after their
+// deadlines, items are dequeued in the order of deadlines, and in FIFO
+// order for the same deadline. It is based on a binary heap, Enqueue and
+// Dequeue take O(log(n)) time.
+//
+// every operations over a DelayQueue are synchronized and
+// safe for concurrent usage.
+type DelayQueue struct {
+	mu    sync.Mutex
+	clock Clock
+	items delayHeap
+	seq   uint64
+	// wait is closed when the earliest deadline changes, to wake up the
+	// Dequeue callers
+	wait chan struct{}
+}
+
+type delayItem struct {
+	value    interface{}
+	deadline time.Time
+	seq      uint64
+}
+
+type delayHeap []*delayItem
+
+func (h delayHeap) Len() int {
+	return len(h)
+}
+
+func (h delayHeap) Less(i, j int) bool {
+	if h[i].deadline.Equal(h[j].deadline) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].deadline.Before(h[j].deadline)
+}
+
+func (h delayHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *delayHeap) Push(x interface{}) {
+	*h = append(*h, x.(*delayItem))
+}
+
+func (h *delayHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return item
+}
+
+// NewDelayQueue creates a DelayQueue driven by clock, SystemClock is used if
+// clock is nil.
+func NewDelayQueue(clock Clock) *DelayQueue {
+	if clock == nil {
+		clock = SystemClock
+	}
+	return &DelayQueue{clock: clock}
+}
+
+// Enqueue adds an item which becomes available at deadline
+func (q *DelayQueue) Enqueue(item interface{}, deadline time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.seq++
+	heap.Push(&q.items, &delayItem{value: item, deadline: deadline, seq: q.seq})
+	if q.items[0].seq == q.seq && q.wait != nil {
+		close(q.wait)
+		q.wait = nil
+	}
+}
+
+// EnqueueAfter adds an item which becomes available after delay
+func (q *DelayQueue) EnqueueAfter(item interface{}, delay time.Duration) {
+	q.Enqueue(item, q.clock.Now().Add(delay))
+}
+
+// Poll removes and returns the earliest item if its deadline has passed
+func (q *DelayQueue) Poll() (interface{}, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.items) == 0 || q.items[0].deadline.After(q.clock.Now()) {
+		return nil, false
+	}
+	return heap.Pop(&q.items).(*delayItem).value, true
+}
+
+// Dequeue removes and returns the earliest item, it blocks until the deadline
+// of the item passes, or returns the error of ctx if ctx is done first.
+func (q *DelayQueue) Dequeue(ctx context.Context) (interface{}, error) {
+	for {
+		q.mu.Lock()
+		var timer Timer
+		var timeout <-chan time.Time
+		if len(q.items) > 0 {
+			now := q.clock.Now()
+			if !q.items[0].deadline.After(now) {
+				item := heap.Pop(&q.items).(*delayItem).value
+				q.mu.Unlock()
+				return item, nil
+			}
+			timer = q.clock.NewTimer(q.items[0].deadline.Sub(now))
+			timeout = timer.C()
+		}
+		if q.wait == nil {
+			q.wait = make(chan struct{})
+		}
+		wait := q.wait
+		q.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-timeout:
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return nil, ctx.Err()
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// Peek returns the earliest item and its deadline without removing it
+func (q *DelayQueue) Peek() (item interface{}, deadline time.Time, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.items) == 0 {
+		return nil, time.Time{}, false
+	}
+	return q.items[0].value, q.items[0].deadline, true
+}
+
+// Size returns the number of items, including the items not due yet
+func (q *DelayQueue) Size() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.items)
+}
+
+// IsEmpty checks if the queue is empty
+func (q *DelayQueue) IsEmpty() bool {
+	return q.Size() == 0
+}
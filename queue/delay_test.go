@@ -0,0 +1,110 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDelayQueue(t *testing.T) {
+	clock := NewManualClock(time.Unix(1000, 0))
+	queue := NewDelayQueue(clock)
+
+	queue.EnqueueAfter("c", 3*time.Second)
+	queue.EnqueueAfter("a", time.Second)
+	queue.EnqueueAfter("b", time.Second)
+	if queue.Size() != 3 {
+		t.Errorf("Got %v expected %v for size", queue.Size(), 3)
+	}
+	if item, deadline, ok := queue.Peek(); !ok || item != "a" || !deadline.Equal(time.Unix(1001, 0)) {
+		t.Errorf("Got %v, %v expected %v, %v for peek", item, deadline, "a", time.Unix(1001, 0))
+	}
+	if _, ok := queue.Poll(); ok {
+		t.Error("Poll an item before its deadline")
+	}
+
+	clock.Advance(time.Second)
+	for _, expected := range []string{"a", "b"} {
+		if item, ok := queue.Poll(); !ok || item != expected {
+			t.Errorf("Got %v, %v expected %v, %v for poll", item, ok, expected, true)
+		}
+	}
+	if _, ok := queue.Poll(); ok {
+		t.Error("Poll an item before its deadline")
+	}
+
+	result := make(chan interface{})
+	go func() {
+		item, err := queue.Dequeue(context.Background())
+		if err != nil {
+			t.Error(err)
+		}
+		result <- item
+	}()
+
+	// the consumer waits for the deadline of "c"
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	select {
+	case item := <-result:
+		t.Errorf("Dequeue %v before its deadline", item)
+	default:
+	}
+	clock.Advance(time.Second)
+	if item := <-result; item != "c" {
+		t.Errorf("Got %v expected %v for dequeue", item, "c")
+	}
+}
+
+func TestDelayQueueEarlierItem(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	queue := NewDelayQueue(clock)
+	queue.EnqueueAfter("late", time.Hour)
+
+	result := make(chan interface{})
+	go func() {
+		item, _ := queue.Dequeue(context.Background())
+		result <- item
+	}()
+	clock.BlockUntil(1)
+
+	// an earlier item wakes up the consumer to wait for the new deadline
+	queue.EnqueueAfter("early", time.Minute)
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+	if item := <-result; item != "early" {
+		t.Errorf("Got %v expected %v for dequeue", item, "early")
+	}
+
+	// an item enqueued to the empty queue wakes up the consumer
+	queue = NewDelayQueue(clock)
+	go func() {
+		item, _ := queue.Dequeue(context.Background())
+		result <- item
+	}()
+	queue.EnqueueAfter("now", 0)
+	if item := <-result; item != "now" {
+		t.Errorf("Got %v expected %v for dequeue", item, "now")
+	}
+}
+
+func TestDelayQueueCancel(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	queue := NewDelayQueue(clock)
+	queue.EnqueueAfter(1, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error)
+	go func() {
+		_, err := queue.Dequeue(ctx)
+		result <- err
+	}()
+	clock.BlockUntil(1)
+	cancel()
+	if err := <-result; err != context.Canceled {
+		t.Errorf("Got %v expected %v for canceled dequeue", err, context.Canceled)
+	}
+	if clock.Timers() != 0 || queue.Size() != 1 {
+		t.Error("Canceled dequeue leaks the timer or the item")
+	}
+}
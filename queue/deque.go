@@ -4,6 +4,7 @@ package queue
 
 import (
 	"container/list"
+	"context"
 	"errors"
 	"sync"
 )
@@ -15,12 +16,38 @@ import (
 // every operations over an Deque are synchronized and
 // safe for concurrent usage.
 
-var CapacityFullErr = errors.New("full capacity")
+var (
+	CapacityFullErr = errors.New("full capacity")
+	// OutOfRangeErr is returned when the index is out of the deque range.
+	OutOfRangeErr = errors.New("index out of range")
+)
+
+// OverflowPolicy decides what Append and Prepend do when a capped deque is full.
+type OverflowPolicy int
+
+const (
+	// RejectOverflow rejects the new item with CapacityFullErr.
+	RejectOverflow OverflowPolicy = iota
+	// DropOldest evicts an item from the opposite end to make room, the
+	// front for Append and the back for Prepend.
+	DropOldest
+	// DropNewest discards the new item.
+	DropNewest
+	// BlockOverflow blocks until there is room. A deque of zero capacity
+	// rejects with CapacityFullErr instead of blocking forever. Use
+	// AppendContext and PrependContext to give up waiting.
+	BlockOverflow
+)
 
 type Deque struct {
 	sync.RWMutex
 	container *list.List
 	capacity  int
+	policy    OverflowPolicy
+	onDrop    func(item interface{})
+	dropped   uint64
+	// notFull is closed when items are removed, to wake up the blocked inserts
+	notFull chan struct{}
 }
 
 // NewDeque creates a Deque.
@@ -30,38 +57,213 @@ func NewDeque() *Deque {
 
 // NewCappedDeque creates a Deque with the specified capacity limit.
 func NewCappedDeque(capacity int) *Deque {
+	return NewCappedDequeWithPolicy(capacity, RejectOverflow)
+}
+
+// NewCappedDequeWithPolicy creates a Deque with the specified capacity limit
+// and the policy applied when it is full.
+func NewCappedDequeWithPolicy(capacity int, policy OverflowPolicy) *Deque {
 	return &Deque{
 		container: list.New(),
 		capacity:  capacity,
+		policy:    policy,
 	}
 }
 
-// Append inserts element at the back of the Deque in a O(1) time complexity,
-// returning true if successful or false if the deque is at capacity.
-func (s *Deque) Append(item interface{}) error {
+// OnDrop sets the callback called with every item dropped by the DropOldest
+// and DropNewest policies. It is called without holding the lock, so it may
+// use the deque.
+func (s *Deque) OnDrop(fn func(item interface{})) {
 	s.Lock()
 	defer s.Unlock()
 
-	if s.capacity < 0 || s.container.Len() < s.capacity {
-		s.container.PushBack(item)
+	s.onDrop = fn
+}
+
+// Dropped returns the number of items dropped by the overflow policy
+func (s *Deque) Dropped() uint64 {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.dropped
+}
+
+// Policy returns the overflow policy of the deque
+func (s *Deque) Policy() OverflowPolicy {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.policy
+}
+
+func (s *Deque) isFull() bool {
+	return s.capacity >= 0 && s.container.Len() >= s.capacity
+}
+
+// waitNotFull unlocks and blocks until items are removed or ctx is done,
+// then locks again.
+func (s *Deque) waitNotFull(ctx context.Context) error {
+	if s.notFull == nil {
+		s.notFull = make(chan struct{})
+	}
+	notFull := s.notFull
+	s.Unlock()
+	defer s.Lock()
+
+	select {
+	case <-notFull:
 		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
+}
 
-	return CapacityFullErr
+// signalNotFull wakes up the blocked inserts, it must be called with the
+// lock held after items are removed.
+func (s *Deque) signalNotFull() {
+	if s.notFull != nil {
+		close(s.notFull)
+		s.notFull = nil
+	}
 }
 
-// Prepend inserts element at the Deques front in a O(1) time complexity,
-// returning true if successful or false if the deque is at capacity.
-func (s *Deque) Prepend(item interface{}) error {
+// room returns the number of items which fit before the deque is full,
+// or -1 if unlimited.
+func (s *Deque) room() int {
+	if s.capacity < 0 {
+		return -1
+	}
+	if room := s.capacity - s.container.Len(); room > 0 {
+		return room
+	}
+	return 0
+}
+
+func (s *Deque) push(item interface{}, front bool) {
+	if front {
+		s.container.PushFront(item)
+	} else {
+		s.container.PushBack(item)
+	}
+}
+
+// insert inserts items in turn at the front or the back by the overflow
+// policy. The RejectOverflow and BlockOverflow policies insert all or nothing.
+func (s *Deque) insert(ctx context.Context, front bool, items ...interface{}) error {
+	s.Lock()
+	if s.capacity >= 0 && s.policy != DropOldest && s.policy != DropNewest {
+		if len(items) > s.capacity {
+			s.Unlock()
+			return CapacityFullErr
+		}
+		for s.policy == BlockOverflow && s.room() < len(items) {
+			if err := s.waitNotFull(ctx); err != nil {
+				s.Unlock()
+				return err
+			}
+		}
+		if s.room() < len(items) {
+			s.Unlock()
+			return CapacityFullErr
+		}
+	}
+
+	var dropped []interface{}
+	for _, item := range items {
+		if s.isFull() {
+			if s.policy != DropOldest || s.container.Len() == 0 {
+				dropped = append(dropped, item)
+				continue
+			}
+			if front {
+				dropped = append(dropped, s.container.Remove(s.container.Back()))
+			} else {
+				dropped = append(dropped, s.container.Remove(s.container.Front()))
+			}
+		}
+		s.push(item, front)
+	}
+
+	s.dropped += uint64(len(dropped))
+	onDrop := s.onDrop
+	s.Unlock()
+
+	if onDrop != nil {
+		for _, item := range dropped {
+			onDrop(item)
+		}
+	}
+	return nil
+}
+
+// insertSome inserts items in turn until the deque is full.
+func (s *Deque) insertSome(front bool, items ...interface{}) int {
 	s.Lock()
 	defer s.Unlock()
 
-	if s.capacity < 0 || s.container.Len() < s.capacity {
-		s.container.PushFront(item)
-		return nil
+	n := len(items)
+	if room := s.room(); room >= 0 && room < n {
+		n = room
 	}
+	for _, item := range items[:n] {
+		s.push(item, front)
+	}
+	return n
+}
+
+// Append inserts element at the back of the Deque in a O(1) time complexity,
+// if the deque is at capacity, it is handled by the overflow policy, which
+// returns CapacityFullErr by default.
+func (s *Deque) Append(item interface{}) error {
+	return s.insert(context.Background(), false, item)
+}
+
+// AppendContext is like Append, but if the BlockOverflow policy blocks, it
+// gives up and returns ctx.Err() when ctx is done.
+func (s *Deque) AppendContext(ctx context.Context, item interface{}) error {
+	return s.insert(ctx, false, item)
+}
 
-	return CapacityFullErr
+// Prepend inserts element at the Deques front in a O(1) time complexity,
+// if the deque is at capacity, it is handled by the overflow policy, which
+// returns CapacityFullErr by default.
+func (s *Deque) Prepend(item interface{}) error {
+	return s.insert(context.Background(), true, item)
+}
+
+// PrependContext is like Prepend, but if the BlockOverflow policy blocks, it
+// gives up and returns ctx.Err() when ctx is done.
+func (s *Deque) PrependContext(ctx context.Context, item interface{}) error {
+	return s.insert(ctx, true, item)
+}
+
+// AppendAll inserts items in turn at the back of the Deque under a single
+// lock. If there is no room for all of them, RejectOverflow returns
+// CapacityFullErr and BlockOverflow blocks, none is inserted in both cases,
+// while the drop policies drop items one by one like Append.
+func (s *Deque) AppendAll(items ...interface{}) error {
+	return s.insert(context.Background(), false, items...)
+}
+
+// PrependAll inserts items in turn at the front of the Deque under a single
+// lock, like calling Prepend for each of them, so they end up in reverse
+// order. Capacity is handled like AppendAll.
+func (s *Deque) PrependAll(items ...interface{}) error {
+	return s.insert(context.Background(), true, items...)
+}
+
+// AppendSome inserts items in turn at the back of the Deque until it is full,
+// regardless of the overflow policy, and returns the number of items
+// inserted, which are the first ones of items.
+func (s *Deque) AppendSome(items ...interface{}) int {
+	return s.insertSome(false, items...)
+}
+
+// PrependSome inserts items in turn at the front of the Deque until it is
+// full, regardless of the overflow policy, and returns the number of items
+// inserted, which are the first ones of items.
+func (s *Deque) PrependSome(items ...interface{}) int {
+	return s.insertSome(true, items...)
 }
 
 // Pop removes the last element of the deque in a O(1) time complexity
@@ -75,6 +277,7 @@ func (s *Deque) Pop() interface{} {
 	lastContainerItem = s.container.Back()
 	if lastContainerItem != nil {
 		item = s.container.Remove(lastContainerItem)
+		s.signalNotFull()
 	}
 
 	return item
@@ -91,11 +294,72 @@ func (s *Deque) Shift() interface{} {
 	firstContainerItem = s.container.Front()
 	if firstContainerItem != nil {
 		item = s.container.Remove(firstContainerItem)
+		s.signalNotFull()
 	}
 
 	return item
 }
 
+// ShiftN removes up to n elements from the front of the deque under a single
+// lock and returns them in order, the first element first.
+func (s *Deque) ShiftN(n int) []interface{} {
+	s.Lock()
+	defer s.Unlock()
+
+	items := []interface{}{}
+	for len(items) < n && s.container.Len() > 0 {
+		items = append(items, s.container.Remove(s.container.Front()))
+	}
+	if len(items) > 0 {
+		s.signalNotFull()
+	}
+	return items
+}
+
+// PopN removes up to n elements from the back of the deque under a single
+// lock and returns them in the order Pop would, the last element first.
+func (s *Deque) PopN(n int) []interface{} {
+	s.Lock()
+	defer s.Unlock()
+
+	items := []interface{}{}
+	for len(items) < n && s.container.Len() > 0 {
+		items = append(items, s.container.Remove(s.container.Back()))
+	}
+	if len(items) > 0 {
+		s.signalNotFull()
+	}
+	return items
+}
+
+// Drain removes all elements of the deque and returns them in order, the
+// first element first.
+func (s *Deque) Drain() []interface{} {
+	s.Lock()
+	defer s.Unlock()
+
+	items := make([]interface{}, 0, s.container.Len())
+	for e := s.container.Front(); e != nil; e = e.Next() {
+		items = append(items, e.Value)
+	}
+	if len(items) > 0 {
+		s.container.Init()
+		s.signalNotFull()
+	}
+	return items
+}
+
+// DrainTo removes all elements of the deque and calls fn for each of them in
+// order, and returns the number of elements. fn is called after the lock is
+// released, so it may use the deque.
+func (s *Deque) DrainTo(fn func(item interface{})) int {
+	items := s.Drain()
+	for _, item := range items {
+		fn(item)
+	}
+	return len(items)
+}
+
 // First returns the first value stored in the deque in a O(1) time complexity
 func (s *Deque) First() interface{} {
 	s.RLock()
@@ -122,6 +386,164 @@ func (s *Deque) Last() interface{} {
 	}
 }
 
+// element returns the i-th element, walking from the nearer end.
+func (s *Deque) element(i int) *list.Element {
+	if i < s.container.Len()/2 {
+		e := s.container.Front()
+		for ; i > 0; i-- {
+			e = e.Next()
+		}
+		return e
+	}
+
+	e := s.container.Back()
+	for i = s.container.Len() - 1 - i; i > 0; i-- {
+		e = e.Prev()
+	}
+	return e
+}
+
+// At returns the i-th element from the front of the deque in a O(n) time
+// complexity, or OutOfRangeErr if i is not in [0, Size()).
+func (s *Deque) At(i int) (interface{}, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	if i < 0 || i >= s.container.Len() {
+		return nil, OutOfRangeErr
+	}
+	return s.element(i).Value, nil
+}
+
+// IndexOf returns the index of the first element from the front satisfies
+// pred, or -1 if not found. pred is called with the lock held, so it must not
+// use the deque.
+func (s *Deque) IndexOf(pred func(item interface{}) bool) int {
+	s.RLock()
+	defer s.RUnlock()
+
+	i := 0
+	for e := s.container.Front(); e != nil; e = e.Next() {
+		if pred(e.Value) {
+			return i
+		}
+		i++
+	}
+	return -1
+}
+
+// Remove removes every element satisfies pred and returns the number of
+// removed elements. pred is called with the lock held, so it must not use
+// the deque.
+func (s *Deque) Remove(pred func(item interface{}) bool) int {
+	s.Lock()
+	defer s.Unlock()
+
+	removed := 0
+	for e := s.container.Front(); e != nil; {
+		next := e.Next()
+		if pred(e.Value) {
+			s.container.Remove(e)
+			removed++
+		}
+		e = next
+	}
+	if removed > 0 {
+		s.signalNotFull()
+	}
+	return removed
+}
+
+// RemoveAt removes and returns the i-th element from the front of the deque
+// in a O(n) time complexity, or OutOfRangeErr if i is not in [0, Size()).
+func (s *Deque) RemoveAt(i int) (interface{}, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if i < 0 || i >= s.container.Len() {
+		return nil, OutOfRangeErr
+	}
+	item := s.container.Remove(s.element(i))
+	s.signalNotFull()
+	return item, nil
+}
+
+// InsertAt inserts item so that it becomes the i-th element from the front
+// in a O(n) time complexity, i must be in [0, Size()]. The overflow policy
+// does not apply, CapacityFullErr is returned if the deque is full.
+func (s *Deque) InsertAt(i int, item interface{}) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if i < 0 || i > s.container.Len() {
+		return OutOfRangeErr
+	}
+	if s.isFull() {
+		return CapacityFullErr
+	}
+
+	if i == s.container.Len() {
+		s.container.PushBack(item)
+	} else {
+		s.container.InsertBefore(item, s.element(i))
+	}
+	return nil
+}
+
+// Rotate rotates the deque n steps to the back, the last element becomes
+// the first for n = 1, and rotates to the front if n is negative.
+func (s *Deque) Rotate(n int) {
+	s.Lock()
+	defer s.Unlock()
+
+	size := s.container.Len()
+	if size <= 1 {
+		return
+	}
+	// rotate to the back by n is rotating to the front by size - n, take the shorter one
+	n %= size
+	if n < 0 {
+		n += size
+	}
+	if n <= size/2 {
+		for ; n > 0; n-- {
+			s.container.MoveToFront(s.container.Back())
+		}
+	} else {
+		for n = size - n; n > 0; n-- {
+			s.container.MoveToBack(s.container.Front())
+		}
+	}
+}
+
+// Reverse reverses the order of the deque in place.
+func (s *Deque) Reverse() {
+	s.Lock()
+	defer s.Unlock()
+
+	first := s.container.Front()
+	if first == nil {
+		return
+	}
+	for e := first.Next(); e != nil; {
+		next := e.Next()
+		s.container.MoveToFront(e)
+		e = next
+	}
+}
+
+// Values returns a snapshot of the elements from the front to the back.
+func (s *Deque) Values() []interface{} {
+	s.RLock()
+	defer s.RUnlock()
+
+	items := make([]interface{}, 0, s.container.Len())
+	for e := s.container.Front(); e != nil; e = e.Next() {
+		items = append(items, e.Value)
+	}
+	return items
+}
+
 // Size returns the actual deque size
 func (s *Deque) Size() int {
 	s.RLock()
@@ -150,5 +572,5 @@ func (s *Deque) IsFull() bool {
 	s.RLock()
 	defer s.RUnlock()
 
-	return s.capacity >= 0 && s.container.Len() >= s.capacity
+	return s.isFull()
 }
@@ -1,8 +1,10 @@
 package queue
 
 import (
+	"context"
 	"strconv"
 	"testing"
+	"time"
 )
 
 func TestDequeAppend(t *testing.T) {
@@ -281,3 +283,381 @@ func TestDequeFull_non_full_deque(t *testing.T) {
 
 	}
 }
+
+func TestDequeOverflowReject(t *testing.T) {
+	deque := NewCappedDequeWithPolicy(2, RejectOverflow)
+	deque.Append(1)
+	deque.Append(2)
+	if err := deque.Append(3); err != CapacityFullErr {
+		t.Errorf("Got %v expected %v for append", err, CapacityFullErr)
+	}
+	if err := deque.Prepend(0); err != CapacityFullErr {
+		t.Errorf("Got %v expected %v for prepend", err, CapacityFullErr)
+	}
+	if deque.Dropped() != 0 || deque.Policy() != RejectOverflow {
+		t.Error("Rejected items are counted as dropped")
+	}
+}
+
+func TestDequeOverflowDropOldest(t *testing.T) {
+	deque := NewCappedDequeWithPolicy(3, DropOldest)
+	dropped := []interface{}{}
+	deque.OnDrop(func(item interface{}) {
+		dropped = append(dropped, item)
+	})
+
+	for i := 1; i <= 5; i++ {
+		if err := deque.Append(i); err != nil {
+			t.Errorf("Deque Append error %v", err)
+		}
+	}
+	// 1 2 3 -> 3 4 5
+	if deque.First() != 3 || deque.Last() != 5 || deque.Size() != 3 {
+		t.Errorf("Got %v..%v expected %v..%v", deque.First(), deque.Last(), 3, 5)
+	}
+
+	// prepend evicts from the back
+	deque.Prepend(2)
+	if deque.First() != 2 || deque.Last() != 4 {
+		t.Errorf("Got %v..%v expected %v..%v", deque.First(), deque.Last(), 2, 4)
+	}
+
+	if deque.Dropped() != 3 || len(dropped) != 3 || dropped[0] != 1 || dropped[1] != 2 || dropped[2] != 5 {
+		t.Errorf("Got %v, %v expected %v, %v for dropped items", deque.Dropped(), dropped, 3, []int{1, 2, 5})
+	}
+
+	empty := NewCappedDequeWithPolicy(0, DropOldest)
+	if err := empty.Append(1); err != nil || empty.Dropped() != 1 || !empty.IsEmpty() {
+		t.Error("Deque of zero capacity should drop the new item")
+	}
+}
+
+func TestDequeOverflowDropNewest(t *testing.T) {
+	deque := NewCappedDequeWithPolicy(2, DropNewest)
+	var dropped []interface{}
+	deque.OnDrop(func(item interface{}) {
+		// the callback is able to use the deque
+		if deque.Size() != 2 {
+			t.Error("Deque size changed by drop")
+		}
+		dropped = append(dropped, item)
+	})
+
+	deque.Append(1)
+	deque.Append(2)
+	deque.Append(3)
+	deque.Prepend(0)
+	if deque.First() != 1 || deque.Last() != 2 {
+		t.Errorf("Got %v..%v expected %v..%v", deque.First(), deque.Last(), 1, 2)
+	}
+	if deque.Dropped() != 2 || len(dropped) != 2 || dropped[0] != 3 || dropped[1] != 0 {
+		t.Errorf("Got %v expected %v for dropped items", dropped, []int{3, 0})
+	}
+}
+
+func TestDequeOverflowBlock(t *testing.T) {
+	deque := NewCappedDequeWithPolicy(2, BlockOverflow)
+	deque.Append(1)
+	deque.Append(2)
+
+	done := make(chan bool)
+	go func() {
+		deque.Append(3)
+		deque.Prepend(0)
+		done <- true
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Append to full deque not block")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	if item := deque.Shift(); item != 1 {
+		t.Errorf("Got %v expected %v for shift", item, 1)
+	}
+	if item := deque.Pop(); item != 2 && item != 3 {
+		t.Errorf("Got %v expected %v or %v for pop", item, 2, 3)
+	}
+	<-done
+	if deque.Size() != 2 || deque.Dropped() != 0 {
+		t.Errorf("Got %v expected %v for deque size", deque.Size(), 2)
+	}
+
+	zero := NewCappedDequeWithPolicy(0, BlockOverflow)
+	if err := zero.Append(1); err != CapacityFullErr {
+		t.Errorf("Got %v expected %v for deque of zero capacity", err, CapacityFullErr)
+	}
+}
+
+func TestDequeOverflowBlockContext(t *testing.T) {
+	deque := NewCappedDequeWithPolicy(1, BlockOverflow)
+	deque.Append(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- deque.AppendContext(ctx, 2)
+	}()
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Got %v expected %v for canceled append", err, context.Canceled)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := deque.PrependContext(ctx, 0); err != context.DeadlineExceeded {
+		t.Errorf("Got %v expected %v for timed out prepend", err, context.DeadlineExceeded)
+	}
+	if deque.Size() != 1 || deque.First() != 1 {
+		t.Errorf("Got %v, %v expected %v, %v for size and first", deque.Size(), deque.First(), 1, 1)
+	}
+
+	deque.Shift()
+	if err := deque.PrependContext(context.Background(), 0); err != nil || deque.First() != 0 {
+		t.Errorf("Got %v, %v expected %v, %v for prepend with room", err, deque.First(), nil, 0)
+	}
+}
+
+func TestDequeAppendAll(t *testing.T) {
+	deque := NewCappedDeque(5)
+	if err := deque.AppendAll(1, 2, 3); err != nil {
+		t.Errorf("Deque AppendAll error %v", err)
+	}
+	if err := deque.PrependAll(0, -1); err != nil {
+		t.Errorf("Deque PrependAll error %v", err)
+	}
+	if err := deque.AppendAll(4); err != CapacityFullErr {
+		t.Errorf("Got %v expected %v for append to full deque", err, CapacityFullErr)
+	}
+
+	// all or nothing
+	deque.Shift()
+	if err := deque.AppendAll(4, 5); err != CapacityFullErr || deque.Size() != 4 {
+		t.Errorf("Got %v, %v expected %v, %v", err, deque.Size(), CapacityFullErr, 4)
+	}
+
+	// partial
+	if n := deque.AppendSome(4, 5); n != 1 || deque.Last() != 4 {
+		t.Errorf("Got %v, %v expected %v, %v", n, deque.Last(), 1, 4)
+	}
+	deque.ShiftN(2)
+	if n := deque.PrependSome(1, 0, -1); n != 2 || deque.First() != 0 {
+		t.Errorf("Got %v, %v expected %v, %v", n, deque.First(), 2, 0)
+	}
+
+	expected := []interface{}{0, 1, 2, 3, 4}
+	if items := deque.Drain(); !equalItems(items, expected) {
+		t.Errorf("Got %v expected %v for deque items", items, expected)
+	}
+}
+
+func TestDequeAppendAllPolicy(t *testing.T) {
+	deque := NewCappedDequeWithPolicy(3, DropOldest)
+	var dropped []interface{}
+	deque.OnDrop(func(item interface{}) {
+		dropped = append(dropped, item)
+	})
+	deque.AppendAll(1, 2, 3, 4, 5)
+	if items := deque.Drain(); !equalItems(items, []interface{}{3, 4, 5}) {
+		t.Errorf("Got %v expected %v for deque items", items, []interface{}{3, 4, 5})
+	}
+	if deque.Dropped() != 2 || !equalItems(dropped, []interface{}{1, 2}) {
+		t.Errorf("Got %v expected %v for dropped items", dropped, []interface{}{1, 2})
+	}
+
+	deque = NewCappedDequeWithPolicy(3, DropNewest)
+	deque.AppendAll(1, 2, 3, 4, 5)
+	if items := deque.Drain(); !equalItems(items, []interface{}{1, 2, 3}) || deque.Dropped() != 2 {
+		t.Errorf("Got %v expected %v for deque items", items, []interface{}{1, 2, 3})
+	}
+
+	deque = NewCappedDequeWithPolicy(3, BlockOverflow)
+	if err := deque.AppendAll(1, 2, 3, 4); err != CapacityFullErr {
+		t.Errorf("Got %v expected %v for items more than capacity", err, CapacityFullErr)
+	}
+	deque.AppendAll(1, 2)
+	done := make(chan bool)
+	go func() {
+		deque.AppendAll(3, 4)
+		done <- true
+	}()
+	select {
+	case <-done:
+		t.Fatal("AppendAll to deque without enough room not block")
+	case <-time.After(10 * time.Millisecond):
+	}
+	if items := deque.ShiftN(1); !equalItems(items, []interface{}{1}) {
+		t.Errorf("Got %v expected %v for shifted items", items, []interface{}{1})
+	}
+	<-done
+	if items := deque.Drain(); !equalItems(items, []interface{}{2, 3, 4}) {
+		t.Errorf("Got %v expected %v for deque items", items, []interface{}{2, 3, 4})
+	}
+}
+
+func TestDequeShiftN(t *testing.T) {
+	deque := NewDeque()
+	deque.AppendAll(1, 2, 3, 4, 5, 6)
+
+	if items := deque.ShiftN(2); !equalItems(items, []interface{}{1, 2}) {
+		t.Errorf("Got %v expected %v for shifted items", items, []interface{}{1, 2})
+	}
+	if items := deque.PopN(2); !equalItems(items, []interface{}{6, 5}) {
+		t.Errorf("Got %v expected %v for popped items", items, []interface{}{6, 5})
+	}
+	if items := deque.PopN(0); len(items) != 0 {
+		t.Errorf("Got %v expected empty for PopN(0)", items)
+	}
+	if items := deque.ShiftN(10); !equalItems(items, []interface{}{3, 4}) {
+		t.Errorf("Got %v expected %v for shifted items", items, []interface{}{3, 4})
+	}
+	if items := deque.Drain(); len(items) != 0 {
+		t.Errorf("Got %v expected empty for drain", items)
+	}
+
+	deque.AppendAll(1, 2, 3)
+	var drained []interface{}
+	n := deque.DrainTo(func(item interface{}) {
+		drained = append(drained, item)
+		// the lock is released
+		deque.Size()
+	})
+	if n != 3 || !equalItems(drained, []interface{}{1, 2, 3}) || !deque.IsEmpty() {
+		t.Errorf("Got %v, %v expected %v, %v for drained items", n, drained, 3, []interface{}{1, 2, 3})
+	}
+}
+
+func equalItems(a, b []interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func BenchmarkDequeAppend(b *testing.B) {
+	items := make([]interface{}, 1000)
+	for i := 0; i < b.N; i++ {
+		deque := NewDeque()
+		for _, item := range items {
+			deque.Append(item)
+		}
+	}
+}
+
+func BenchmarkDequeAppendAll(b *testing.B) {
+	items := make([]interface{}, 1000)
+	for i := 0; i < b.N; i++ {
+		deque := NewDeque()
+		deque.AppendAll(items...)
+	}
+}
+
+func TestDequeAt(t *testing.T) {
+	deque := NewDeque()
+	for i := 0; i < 10; i++ {
+		deque.Append(i)
+	}
+
+	for i := 0; i < 10; i++ {
+		if item, err := deque.At(i); err != nil || item != i {
+			t.Errorf("Got %v, %v expected %v for At(%v)", item, err, i, i)
+		}
+	}
+	for _, i := range []int{-1, 10} {
+		if _, err := deque.At(i); err != OutOfRangeErr {
+			t.Errorf("Got %v expected %v for At(%v)", err, OutOfRangeErr, i)
+		}
+	}
+
+	isSeven := func(item interface{}) bool { return item == 7 }
+	if i := deque.IndexOf(isSeven); i != 7 {
+		t.Errorf("Got %v expected %v for IndexOf", i, 7)
+	}
+	if n := deque.Remove(isSeven); n != 1 || deque.IndexOf(isSeven) != -1 {
+		t.Errorf("Got %v expected %v for Remove", n, 1)
+	}
+	isEven := func(item interface{}) bool { return item.(int)%2 == 0 }
+	if n := deque.Remove(isEven); n != 5 {
+		t.Errorf("Got %v expected %v for Remove", n, 5)
+	}
+	expected := []interface{}{1, 3, 5, 9}
+	if items := deque.Values(); !equalItems(items, expected) {
+		t.Errorf("Got %v expected %v for deque values", items, expected)
+	}
+}
+
+func TestDequeInsertAt(t *testing.T) {
+	deque := NewCappedDeque(6)
+	deque.AppendAll(1, 3)
+
+	for _, c := range []struct{ index, item int }{{0, 0}, {2, 2}, {4, 5}, {4, 4}} {
+		if err := deque.InsertAt(c.index, c.item); err != nil {
+			t.Errorf("Got %v for InsertAt(%v, %v)", err, c.index, c.item)
+		}
+	}
+	expected := []interface{}{0, 1, 2, 3, 4, 5}
+	if items := deque.Values(); !equalItems(items, expected) {
+		t.Errorf("Got %v expected %v for deque values", items, expected)
+	}
+	if err := deque.InsertAt(0, -1); err != CapacityFullErr {
+		t.Errorf("Got %v expected %v for InsertAt to full deque", err, CapacityFullErr)
+	}
+
+	if item, err := deque.RemoveAt(4); err != nil || item != 4 {
+		t.Errorf("Got %v, %v expected %v for RemoveAt(4)", item, err, 4)
+	}
+	if item, err := deque.RemoveAt(0); err != nil || item != 0 {
+		t.Errorf("Got %v, %v expected %v for RemoveAt(0)", item, err, 0)
+	}
+	if _, err := deque.RemoveAt(4); err != OutOfRangeErr {
+		t.Errorf("Got %v expected %v for RemoveAt(4)", err, OutOfRangeErr)
+	}
+	if err := deque.InsertAt(5, 6); err != OutOfRangeErr {
+		t.Errorf("Got %v expected %v for InsertAt(5)", err, OutOfRangeErr)
+	}
+	expected = []interface{}{1, 2, 3, 5}
+	if items := deque.Values(); !equalItems(items, expected) {
+		t.Errorf("Got %v expected %v for deque values", items, expected)
+	}
+}
+
+func TestDequeRotate(t *testing.T) {
+	cases := []struct {
+		n        int
+		expected []interface{}
+	}{
+		{0, []interface{}{0, 1, 2, 3, 4}},
+		{1, []interface{}{4, 0, 1, 2, 3}},
+		{4, []interface{}{1, 2, 3, 4, 0}},
+		{-1, []interface{}{1, 2, 3, 4, 0}},
+		{-7, []interface{}{2, 3, 4, 0, 1}},
+		{12, []interface{}{3, 4, 0, 1, 2}},
+	}
+	for _, c := range cases {
+		deque := NewDeque()
+		deque.AppendAll(0, 1, 2, 3, 4)
+		deque.Rotate(c.n)
+		if items := deque.Values(); !equalItems(items, c.expected) {
+			t.Errorf("Got %v expected %v for Rotate(%v)", items, c.expected, c.n)
+		}
+	}
+
+	deque := NewDeque()
+	deque.Rotate(3)
+	deque.Reverse()
+	deque.AppendAll(0, 1, 2, 3)
+	deque.Reverse()
+	expected := []interface{}{3, 2, 1, 0}
+	if items := deque.Values(); !equalItems(items, expected) {
+		t.Errorf("Got %v expected %v for Reverse", items, expected)
+	}
+	if deque.First() != 3 || deque.Last() != 0 {
+		t.Errorf("Got %v..%v expected %v..%v", deque.First(), deque.Last(), 3, 0)
+	}
+}
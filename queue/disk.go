@@ -0,0 +1,598 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+var (
+	// CorruptedQueueErr is returned when the files of a DiskQueue are corrupted.
+	CorruptedQueueErr = errors.New("corrupted queue data")
+	// DiskQueueClosedErr is returned when the DiskQueue is used after Close.
+	DiskQueueClosedErr = errors.New("disk queue closed")
+	// ItemTypeErr is returned when the item type does not match the codec.
+	ItemTypeErr = errors.New("item type not match the codec")
+)
+
+// ItemCodec encodes and decodes the items of a DiskQueue.
+type ItemCodec interface {
+	Encode(item interface{}) ([]byte, error)
+	Decode(data []byte) (interface{}, error)
+}
+
+type bytesItemCodec struct{}
+
+func (bytesItemCodec) Encode(item interface{}) ([]byte, error) {
+	data, ok := item.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ItemTypeErr, item)
+	}
+	return data, nil
+}
+
+func (bytesItemCodec) Decode(data []byte) (interface{}, error) {
+	return data, nil
+}
+
+type stringItemCodec struct{}
+
+func (stringItemCodec) Encode(item interface{}) ([]byte, error) {
+	s, ok := item.(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ItemTypeErr, item)
+	}
+	return []byte(s), nil
+}
+
+func (stringItemCodec) Decode(data []byte) (interface{}, error) {
+	return string(data), nil
+}
+
+var (
+	// BytesItemCodec stores []byte items as is.
+	BytesItemCodec ItemCodec = bytesItemCodec{}
+	// StringItemCodec stores string items.
+	StringItemCodec ItemCodec = stringItemCodec{}
+)
+
+// SyncPolicy decides when a DiskQueue flushes its files to the disk.
+type SyncPolicy int
+
+const (
+	// SyncAlways flushes after every Enqueue and Dequeue, nothing is lost
+	// after a crash.
+	SyncAlways SyncPolicy = iota
+	// SyncBatch flushes after every SyncEvery writes and every SyncEvery
+	// dequeues, the last unflushed items may be lost or dequeued again after
+	// a crash.
+	SyncBatch
+	// SyncNever leaves flushing to the operating system, only Sync and Close
+	// flush.
+	SyncNever
+)
+
+// DiskQueueOptions configures a DiskQueue, zero values are replaced by defaults.
+type DiskQueueOptions struct {
+	// Codec encodes the items, BytesItemCodec by default.
+	Codec ItemCodec
+	// SegmentSize is the size in bytes at which a new segment file is
+	// started, 64MB by default.
+	SegmentSize int64
+	// Sync is the flush policy, SyncAlways by default.
+	Sync SyncPolicy
+	// SyncEvery is the batch size of SyncBatch, 100 by default.
+	SyncEvery int
+	// CacheSize is the number of items decoded ahead of the head, 128 by default.
+	CacheSize int
+}
+
+// DiskQueue is a FIFO queue stored in segment files of a directory, so that
+// it survives restarts and holds more items than fit in memory. The items
+// next to the head are cached in a Deque, and the items enqueued while the
+// cache is not full go to the cache directly, so a queue which keeps up
+// with its producers does not read the files back.
+//
+// Fully dequeued segments are removed, and Compact rewrites the head segment
+// to reclaim the space of its dequeued items. When it is opened, every item
+// not dequeued yet is verified, the partially written items left by a crash
+// in the last segment are discarded, and an invalid item in the other
+// segments is reported as CorruptedQueueErr. After a crash the items are
+// dequeued from the last flushed head, which is at least once delivery with
+// the SyncBatch and SyncNever policies.
+//
+// every operations over a DiskQueue are synchronized and
+// safe for concurrent usage, but a directory must be used by one DiskQueue.
+type DiskQueue struct {
+	mu      sync.Mutex
+	dir     string
+	options DiskQueueOptions
+
+	// segments in ascending order, the head is in the first one and the
+	// last one is written
+	segments []*segment
+	writer   *os.File
+	tail     uint64
+	unsynced int
+
+	head    diskPosition
+	headSeq uint64
+	unsaved int
+
+	// the position and the sequence number of the next record to be cached
+	read       diskPosition
+	readSeq    uint64
+	reader     *os.File
+	readerFrom uint64
+
+	cache  *Deque
+	closed bool
+}
+
+type diskEntry struct {
+	item interface{}
+	// end is the position after the record of item
+	end diskPosition
+}
+
+// NewDiskQueue opens the DiskQueue stored in dir, the directory is created
+// if it does not exist.
+func NewDiskQueue(dir string, options DiskQueueOptions) (*DiskQueue, error) {
+	if options.Codec == nil {
+		options.Codec = BytesItemCodec
+	}
+	if options.SegmentSize <= 0 {
+		options.SegmentSize = 64 << 20
+	}
+	if options.SyncEvery <= 0 {
+		options.SyncEvery = 100
+	}
+	if options.CacheSize <= 0 {
+		options.CacheSize = 128
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	q := &DiskQueue{
+		dir:     dir,
+		options: options,
+		cache:   NewCappedDeque(options.CacheSize),
+	}
+	if err := q.recover(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// recover loads the segments and the head, and opens the last segment for writing.
+func (q *DiskQueue) recover() error {
+	segments, err := listSegments(q.dir)
+	if err != nil {
+		return err
+	}
+	headSeq, head, found, err := readMeta(q.dir)
+	if err != nil {
+		return err
+	}
+
+	if len(segments) == 0 {
+		seg := &segment{start: headSeq}
+		if err := writeFile(segmentPath(q.dir, seg.start), nil, true); err != nil {
+			return err
+		}
+		segments = append(segments, seg)
+	}
+
+	// the head is in the last segment starts before it, the segments before
+	// are dequeued or replaced by Compact
+	i := len(segments) - 1
+	for i > 0 && segments[i].start > headSeq {
+		i--
+	}
+	for _, seg := range segments[:i] {
+		if err := os.Remove(segmentPath(q.dir, seg.start)); err != nil {
+			return err
+		}
+	}
+	q.segments = segments[i:]
+
+	last := q.segments[len(q.segments)-1]
+	count, err := recoverSegment(q.dir, last)
+	if err != nil {
+		return err
+	}
+	q.tail = last.start + count
+
+	first := q.segments[0]
+	if headSeq < first.start {
+		headSeq, head = first.start, diskPosition{segment: first.start}
+	} else if !found || head.segment != first.start || head.offset > first.size {
+		// locate the head by skipping the dequeued records
+		skipped, offset, err := skipRecords(q.dir, first, headSeq-first.start)
+		if err != nil {
+			return err
+		}
+		headSeq, head = first.start+skipped, diskPosition{segment: first.start, offset: offset}
+	}
+	if headSeq > q.tail {
+		// the enqueued items are lost, but not the dequeued ones
+		headSeq, head = q.tail, diskPosition{segment: last.start, offset: last.size}
+	}
+	q.head, q.headSeq = head, headSeq
+	q.read, q.readSeq = head, headSeq
+
+	// verify the records to be replayed in the read only segments
+	for i, seg := range q.segments[:len(q.segments)-1] {
+		if seg.start < head.segment {
+			continue
+		}
+		start, offset := seg.start, int64(0)
+		if seg.start == head.segment {
+			start, offset = headSeq, head.offset
+		}
+		count, err := checkSegment(q.dir, seg, offset)
+		if err != nil {
+			return err
+		}
+		if start+count != q.segments[i+1].start {
+			return fmt.Errorf("%w: %s has %d records", CorruptedQueueErr, segmentPath(q.dir, seg.start), count)
+		}
+	}
+
+	q.writer, err = os.OpenFile(segmentPath(q.dir, last.start), os.O_WRONLY|os.O_APPEND, 0)
+	return err
+}
+
+func (q *DiskQueue) segment(start uint64) (int, *segment) {
+	for i, seg := range q.segments {
+		if seg.start == start {
+			return i, seg
+		}
+	}
+	return -1, nil
+}
+
+// Enqueue adds an item at the back of the queue. The encoded item is decoded
+// before it is written, an item which the codec fails to decode is rejected
+// with the error, so that it never blocks the queue.
+func (q *DiskQueue) Enqueue(item interface{}) error {
+	data, err := q.options.Codec.Encode(item)
+	if err != nil {
+		return err
+	}
+	// decoded from the record, since the caller may reuse the item
+	record := appendRecord(nil, data)
+	decoded, err := q.options.Codec.Decode(record[recordHeaderSize:])
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return DiskQueueClosedErr
+	}
+
+	last := q.segments[len(q.segments)-1]
+	if last.size > 0 && last.size+int64(len(record)) > q.options.SegmentSize {
+		if err := q.roll(); err != nil {
+			return err
+		}
+		last = q.segments[len(q.segments)-1]
+	}
+
+	if _, err := q.writer.Write(record); err != nil {
+		// discard the partially written record
+		q.writer.Truncate(last.size)
+		return err
+	}
+	last.size += int64(len(record))
+	q.tail++
+
+	q.unsynced++
+	if q.options.Sync == SyncAlways || (q.options.Sync == SyncBatch && q.unsynced >= q.options.SyncEvery) {
+		if err := q.writer.Sync(); err != nil {
+			return err
+		}
+		q.unsynced = 0
+	}
+
+	// the item is next to be cached, cache it without reading it back
+	if q.readSeq == q.tail-1 && !q.cache.IsFull() {
+		q.read = diskPosition{segment: last.start, offset: last.size}
+		q.readSeq++
+		q.cache.Append(&diskEntry{item: decoded, end: q.read})
+	}
+	return nil
+}
+
+// roll starts a new segment for writing.
+func (q *DiskQueue) roll() error {
+	if q.options.Sync != SyncNever {
+		if err := q.writer.Sync(); err != nil {
+			return err
+		}
+	}
+	if err := q.writer.Close(); err != nil {
+		return err
+	}
+
+	seg := &segment{start: q.tail}
+	writer, err := os.OpenFile(segmentPath(q.dir, seg.start), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	q.writer = writer
+	q.segments = append(q.segments, seg)
+	q.unsynced = 0
+
+	if q.options.Sync != SyncNever {
+		return syncDir(q.dir)
+	}
+	return nil
+}
+
+// fill caches the items next to the head.
+func (q *DiskQueue) fill() error {
+	for !q.cache.IsFull() && q.readSeq < q.tail {
+		i, seg := q.segment(q.read.segment)
+		if q.read.offset >= seg.size {
+			// the records of the next segment are the next
+			if i+1 >= len(q.segments) {
+				return fmt.Errorf("%w: missing segment after %s", CorruptedQueueErr, segmentPath(q.dir, seg.start))
+			}
+			q.read = diskPosition{segment: q.segments[i+1].start}
+			continue
+		}
+
+		if q.reader == nil || q.readerFrom != seg.start {
+			if q.reader != nil {
+				q.reader.Close()
+			}
+			reader, err := os.Open(segmentPath(q.dir, seg.start))
+			if err != nil {
+				q.reader = nil
+				return err
+			}
+			q.reader, q.readerFrom = reader, seg.start
+		}
+
+		data, next, err := readRecord(q.reader, q.read.offset, seg.size, true)
+		if err == io.EOF || err == CorruptedQueueErr {
+			return fmt.Errorf("%w: %s at %d", CorruptedQueueErr, segmentPath(q.dir, seg.start), q.read.offset)
+		}
+		if err != nil {
+			return err
+		}
+		item, err := q.options.Codec.Decode(data)
+		if err != nil {
+			return err
+		}
+
+		q.read.offset = next
+		q.readSeq++
+		q.cache.Append(&diskEntry{item: item, end: q.read})
+	}
+	return nil
+}
+
+// Dequeue removes and returns the front queue item, or nil if the queue is empty.
+func (q *DiskQueue) Dequeue() (interface{}, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return nil, DiskQueueClosedErr
+	}
+	if err := q.fill(); err != nil {
+		return nil, err
+	}
+	if q.cache.IsEmpty() {
+		return nil, nil
+	}
+
+	entry := q.cache.Shift().(*diskEntry)
+	q.head = entry.end
+	q.headSeq++
+	q.unsaved++
+
+	if q.head.segment != q.segments[0].start {
+		return entry.item, q.release()
+	}
+	if q.options.Sync == SyncAlways || (q.options.Sync == SyncBatch && q.unsaved >= q.options.SyncEvery) {
+		return entry.item, q.saveHead(true)
+	}
+	return entry.item, nil
+}
+
+// release removes the segments before the head, after the head is saved.
+func (q *DiskQueue) release() error {
+	if err := q.saveHead(q.options.Sync != SyncNever); err != nil {
+		return err
+	}
+
+	i, _ := q.segment(q.head.segment)
+	for _, seg := range q.segments[:i] {
+		if err := os.Remove(segmentPath(q.dir, seg.start)); err != nil {
+			return err
+		}
+	}
+	q.segments = q.segments[i:]
+	return nil
+}
+
+func (q *DiskQueue) saveHead(sync bool) error {
+	if err := writeMeta(q.dir, q.headSeq, q.head, sync); err != nil {
+		return err
+	}
+	q.unsaved = 0
+	return nil
+}
+
+// Head returns the front queue item, or nil if the queue is empty.
+func (q *DiskQueue) Head() (interface{}, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return nil, DiskQueueClosedErr
+	}
+	if err := q.fill(); err != nil {
+		return nil, err
+	}
+	if q.cache.IsEmpty() {
+		return nil, nil
+	}
+	return q.cache.First().(*diskEntry).item, nil
+}
+
+// Size returns the number of items in the queue.
+func (q *DiskQueue) Size() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return int(q.tail - q.headSeq)
+}
+
+// IsEmpty checks if the queue is empty.
+func (q *DiskQueue) IsEmpty() bool {
+	return q.Size() == 0
+}
+
+// Segments returns the number of segment files.
+func (q *DiskQueue) Segments() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.segments)
+}
+
+// Compact rewrites the head segment without its dequeued items.
+func (q *DiskQueue) Compact() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return DiskQueueClosedErr
+	}
+
+	old := q.segments[0]
+	shift := q.head.offset
+	if shift == 0 {
+		return nil
+	}
+	if shift >= old.size && len(q.segments) > 1 {
+		// the head segment is dequeued, the next one starts at the head
+		q.head = diskPosition{segment: q.segments[1].start}
+		return q.release()
+	}
+
+	// the new segment is complete before it is renamed, and the head is
+	// saved before, so a crash leaves either segment usable
+	seg := &segment{start: q.headSeq, size: old.size - shift}
+	path := segmentPath(q.dir, seg.start)
+	if err := copySegment(segmentPath(q.dir, old.start), path+tmpExt, shift, old.size); err != nil {
+		return err
+	}
+	q.head = diskPosition{segment: seg.start}
+	if err := q.saveHead(true); err != nil {
+		return err
+	}
+	if err := os.Rename(path+tmpExt, path); err != nil {
+		return err
+	}
+
+	if q.reader != nil && q.readerFrom == old.start {
+		q.reader.Close()
+		q.reader = nil
+	}
+	if len(q.segments) == 1 {
+		q.writer.Close()
+		writer, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+		if err != nil {
+			return err
+		}
+		q.writer = writer
+	}
+	if err := os.Remove(segmentPath(q.dir, old.start)); err != nil {
+		return err
+	}
+	q.segments[0] = seg
+
+	// move the positions in the old segment to the new one
+	if q.read.segment == old.start {
+		q.read = diskPosition{segment: seg.start, offset: q.read.offset - shift}
+	}
+	for _, item := range q.cache.Values() {
+		entry := item.(*diskEntry)
+		if entry.end.segment == old.start {
+			entry.end = diskPosition{segment: seg.start, offset: entry.end.offset - shift}
+		}
+	}
+	return syncDir(q.dir)
+}
+
+func copySegment(from, to string, offset, size int64) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, io.NewSectionReader(src, offset, size-offset)); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Sync(); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+// Sync flushes the items and the head to the disk.
+func (q *DiskQueue) Sync() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return DiskQueueClosedErr
+	}
+	return q.sync()
+}
+
+func (q *DiskQueue) sync() error {
+	if err := q.writer.Sync(); err != nil {
+		return err
+	}
+	q.unsynced = 0
+	return q.saveHead(true)
+}
+
+// Close flushes and closes the queue.
+func (q *DiskQueue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return nil
+	}
+	q.closed = true
+
+	err := q.sync()
+	if q.reader != nil {
+		q.reader.Close()
+	}
+	if closeErr := q.writer.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,331 @@
+package queue
+
+import (
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "diskqueue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func openDiskQueue(t *testing.T, dir string, options DiskQueueOptions) *DiskQueue {
+	if options.Codec == nil {
+		options.Codec = StringItemCodec
+	}
+	q, err := NewDiskQueue(dir, options)
+	if err != nil {
+		t.Fatalf("NewDiskQueue error %v", err)
+	}
+	return q
+}
+
+func expectDequeue(t *testing.T, q *DiskQueue, expected interface{}) {
+	t.Helper()
+	item, err := q.Dequeue()
+	if err != nil || item != expected {
+		t.Fatalf("Got %v, %v expected %v for dequeue", item, err, expected)
+	}
+}
+
+func TestDiskQueue(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	// small segments and cache so that items are read back from many files
+	q := openDiskQueue(t, dir, DiskQueueOptions{SegmentSize: 64, CacheSize: 3})
+	defer q.Close()
+
+	if item, err := q.Dequeue(); item != nil || err != nil || !q.IsEmpty() {
+		t.Errorf("Got %v, %v expected nil for dequeue from empty queue", item, err)
+	}
+
+	sampleSize := 100
+	for i := 0; i < sampleSize; i++ {
+		if err := q.Enqueue("item-" + strconv.Itoa(i)); err != nil {
+			t.Fatalf("Enqueue error %v", err)
+		}
+	}
+	if q.Size() != sampleSize || q.Segments() <= 1 {
+		t.Errorf("Got %v, %v segments expected %v items in many segments", q.Size(), q.Segments(), sampleSize)
+	}
+	if item, err := q.Head(); item != "item-0" || err != nil {
+		t.Errorf("Got %v, %v expected %v for head", item, err, "item-0")
+	}
+
+	segments := q.Segments()
+	for i := 0; i < sampleSize; i++ {
+		expectDequeue(t, q, "item-"+strconv.Itoa(i))
+		// interleave enqueues and dequeues
+		if i%10 == 0 {
+			q.Enqueue("extra-" + strconv.Itoa(i))
+		}
+	}
+	for i := 0; i < sampleSize; i += 10 {
+		expectDequeue(t, q, "extra-"+strconv.Itoa(i))
+	}
+	if !q.IsEmpty() || q.Segments() >= segments {
+		t.Errorf("Got %v, %v segments expected empty queue and dequeued segments removed", q.Size(), q.Segments())
+	}
+
+	if err := q.Enqueue(1); !errors.Is(err, ItemTypeErr) {
+		t.Errorf("Got %v expected %v for item of wrong type", err, ItemTypeErr)
+	}
+}
+
+func TestDiskQueueReopen(t *testing.T) {
+	for _, policy := range []SyncPolicy{SyncAlways, SyncBatch, SyncNever} {
+		dir := tempDir(t)
+		defer os.RemoveAll(dir)
+
+		options := DiskQueueOptions{SegmentSize: 100, Sync: policy}
+		q := openDiskQueue(t, dir, options)
+		for i := 0; i < 50; i++ {
+			q.Enqueue(strconv.Itoa(i))
+		}
+		for i := 0; i < 20; i++ {
+			expectDequeue(t, q, strconv.Itoa(i))
+		}
+		if err := q.Close(); err != nil {
+			t.Errorf("Close error %v", err)
+		}
+		if _, err := q.Dequeue(); err != DiskQueueClosedErr {
+			t.Errorf("Got %v expected %v for closed queue", err, DiskQueueClosedErr)
+		}
+
+		q = openDiskQueue(t, dir, options)
+		if q.Size() != 30 {
+			t.Errorf("Got %v expected %v for reopened queue size with policy %v", q.Size(), 30, policy)
+		}
+		q.Enqueue("50")
+		for i := 20; i <= 50; i++ {
+			expectDequeue(t, q, strconv.Itoa(i))
+		}
+		q.Close()
+	}
+}
+
+func TestDiskQueueCrash(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	// without Close, every operation is durable with SyncAlways
+	q := openDiskQueue(t, dir, DiskQueueOptions{})
+	for i := 0; i < 10; i++ {
+		q.Enqueue(strconv.Itoa(i))
+	}
+	expectDequeue(t, q, "0")
+	expectDequeue(t, q, "1")
+
+	// a partially written record at the tail
+	path := segmentPath(dir, 0)
+	file, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	var header [recordHeaderSize]byte
+	binary.LittleEndian.PutUint32(header[:4], 100)
+	file.Write(header[:])
+	file.Write([]byte("partial"))
+	file.Close()
+
+	recovered := openDiskQueue(t, dir, DiskQueueOptions{})
+	if recovered.Size() != 8 {
+		t.Errorf("Got %v expected %v for recovered queue size", recovered.Size(), 8)
+	}
+	recovered.Enqueue("10")
+	for i := 2; i <= 10; i++ {
+		expectDequeue(t, recovered, strconv.Itoa(i))
+	}
+	recovered.Close()
+}
+
+func TestDiskQueueBatchRedelivery(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	q := openDiskQueue(t, dir, DiskQueueOptions{Sync: SyncBatch, SyncEvery: 5})
+	for i := 0; i < 10; i++ {
+		q.Enqueue(strconv.Itoa(i))
+	}
+	for i := 0; i < 7; i++ {
+		expectDequeue(t, q, strconv.Itoa(i))
+	}
+
+	// crash without Close, the head is saved after 5 dequeues
+	recovered := openDiskQueue(t, dir, DiskQueueOptions{Sync: SyncBatch, SyncEvery: 5})
+	if recovered.Size() != 5 {
+		t.Errorf("Got %v expected %v for recovered queue size", recovered.Size(), 5)
+	}
+	expectDequeue(t, recovered, "5")
+	recovered.Close()
+}
+
+func TestDiskQueueCorrupted(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	q := openDiskQueue(t, dir, DiskQueueOptions{SegmentSize: 32})
+	for i := 0; i < 10; i++ {
+		q.Enqueue(strconv.Itoa(i))
+	}
+	q.Close()
+
+	// flip a byte of the data of the first record
+	path := segmentPath(dir, 0)
+	data, _ := ioutil.ReadFile(path)
+	data[recordHeaderSize] ^= 0xff
+	ioutil.WriteFile(path, data, 0644)
+
+	// the records to be replayed are verified when opened
+	if _, err := NewDiskQueue(dir, DiskQueueOptions{SegmentSize: 32}); !errors.Is(err, CorruptedQueueErr) {
+		t.Errorf("Got %v expected %v for corrupted record", err, CorruptedQueueErr)
+	}
+
+	ioutil.WriteFile(filepath.Join(dir, metaFile), []byte("meta"), 0644)
+	if _, err := NewDiskQueue(dir, DiskQueueOptions{}); !errors.Is(err, CorruptedQueueErr) {
+		t.Errorf("Got %v expected %v for corrupted meta", err, CorruptedQueueErr)
+	}
+}
+
+func TestDiskQueueReusedBuffer(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	q := openDiskQueue(t, dir, DiskQueueOptions{Codec: BytesItemCodec})
+	defer q.Close()
+	buffer := []byte("a")
+	q.Enqueue(buffer)
+	buffer[0] = 'b'
+	q.Enqueue(buffer)
+
+	for _, expected := range []string{"a", "b"} {
+		item, err := q.Dequeue()
+		if err != nil || string(item.([]byte)) != expected {
+			t.Errorf("Got %s, %v expected %v for dequeue", item, err, expected)
+		}
+	}
+}
+
+// badItemCodec fails to decode the item "bad".
+type badItemCodec struct{}
+
+var errBadItem = errors.New("bad item")
+
+func (badItemCodec) Encode(item interface{}) ([]byte, error) {
+	return StringItemCodec.Encode(item)
+}
+
+func (badItemCodec) Decode(data []byte) (interface{}, error) {
+	if string(data) == "bad" {
+		return nil, errBadItem
+	}
+	return StringItemCodec.Decode(data)
+}
+
+func TestDiskQueueDecodeError(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	// a small cache, so that items are read back from the files too
+	options := DiskQueueOptions{Codec: badItemCodec{}, CacheSize: 1}
+	q := openDiskQueue(t, dir, options)
+	for _, item := range []string{"a", "bad", "b", "c"} {
+		err := q.Enqueue(item)
+		if item == "bad" && err != errBadItem {
+			t.Errorf("Got %v expected %v for enqueue of undecodable item", err, errBadItem)
+		} else if item != "bad" && err != nil {
+			t.Errorf("Enqueue error %v", err)
+		}
+	}
+	if q.Size() != 3 {
+		t.Errorf("Got %v expected %v for size", q.Size(), 3)
+	}
+	q.Close()
+
+	q = openDiskQueue(t, dir, options)
+	defer q.Close()
+	for _, expected := range []string{"a", "b", "c"} {
+		expectDequeue(t, q, expected)
+	}
+}
+
+func TestDiskQueueCompact(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	options := DiskQueueOptions{SegmentSize: 1 << 10, CacheSize: 4}
+	q := openDiskQueue(t, dir, options)
+	for i := 0; i < 20; i++ {
+		q.Enqueue(strconv.Itoa(i))
+	}
+	for i := 0; i < 15; i++ {
+		expectDequeue(t, q, strconv.Itoa(i))
+	}
+
+	path := segmentPath(dir, 0)
+	before, _ := os.Stat(path)
+	if err := q.Compact(); err != nil {
+		t.Fatalf("Compact error %v", err)
+	}
+	after, err := os.Stat(segmentPath(dir, 15))
+	if _, oldErr := os.Stat(path); err != nil || !os.IsNotExist(oldErr) || after.Size() >= before.Size() {
+		t.Fatalf("Compacted segment not replace the head segment")
+	}
+
+	// the compacted segment is still written and read
+	for i := 20; i < 25; i++ {
+		q.Enqueue(strconv.Itoa(i))
+	}
+	for i := 15; i < 18; i++ {
+		expectDequeue(t, q, strconv.Itoa(i))
+	}
+	q.Close()
+
+	q = openDiskQueue(t, dir, options)
+	defer q.Close()
+	for i := 18; i < 25; i++ {
+		expectDequeue(t, q, strconv.Itoa(i))
+	}
+	if err := q.Compact(); err != nil || !q.IsEmpty() {
+		t.Errorf("Got %v, %v expected empty queue after compact", err, q.Size())
+	}
+}
+
+func TestDiskQueueCompactDequeuedSegment(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	q := openDiskQueue(t, dir, DiskQueueOptions{SegmentSize: 20})
+	defer q.Close()
+	for i := 0; i < 4; i++ {
+		q.Enqueue(strconv.Itoa(i))
+	}
+	// every segment holds two records
+	expectDequeue(t, q, "0")
+	expectDequeue(t, q, "1")
+	if err := q.Compact(); err != nil || q.Segments() != 1 {
+		t.Errorf("Got %v, %v expected %v segments after compact", err, q.Segments(), 1)
+	}
+	expectDequeue(t, q, "2")
+	expectDequeue(t, q, "3")
+}
+
+func BenchmarkDiskQueue(b *testing.B) {
+	dir, _ := ioutil.TempDir("", "diskqueue")
+	defer os.RemoveAll(dir)
+
+	q, _ := NewDiskQueue(dir, DiskQueueOptions{Sync: SyncNever})
+	defer q.Close()
+	item := make([]byte, 128)
+	for i := 0; i < b.N; i++ {
+		q.Enqueue(item)
+		q.Dequeue()
+	}
+}
@@ -0,0 +1,137 @@
+package queue
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// LockFreeQueue is an unbounded lock-free FIFO queue of Michael and Scott,
+// it is safe for concurrent usage by multiple producers and consumers
+// without locks, so it scales better than Queue under contention.
+// The garbage collector prevents the ABA problem, since a node is never
+// reused while it is referenced.
+//
+// Reference:
+//   - Simple, Fast, and Practical Non-Blocking and Blocking Concurrent Queue
+//     Algorithms, Maged M. Michael and Michael L. Scott
+type LockFreeQueue struct {
+	// size is the first field to be 64-bit aligned for atomic operations
+	size int64
+	// head points to a dummy node, the front item is in head.next
+	head unsafe.Pointer
+	tail unsafe.Pointer
+}
+
+type lockFreeNode struct {
+	// value points to the item, it is cleared when the node becomes the
+	// dummy node so the item is not retained by the queue
+	value unsafe.Pointer
+	next  unsafe.Pointer
+}
+
+// NewLockFreeQueue creates a LockFreeQueue.
+func NewLockFreeQueue() *LockFreeQueue {
+	dummy := unsafe.Pointer(&lockFreeNode{})
+	return &LockFreeQueue{head: dummy, tail: dummy}
+}
+
+func loadNode(p *unsafe.Pointer) *lockFreeNode {
+	return (*lockFreeNode)(atomic.LoadPointer(p))
+}
+
+func casNode(p *unsafe.Pointer, old, new *lockFreeNode) bool {
+	return atomic.CompareAndSwapPointer(p, unsafe.Pointer(old), unsafe.Pointer(new))
+}
+
+// loadValue returns the item of the node, or false if it is cleared.
+func (n *lockFreeNode) loadValue() (interface{}, bool) {
+	p := atomic.LoadPointer(&n.value)
+	if p == nil {
+		return nil, false
+	}
+	return *(*interface{})(p), true
+}
+
+// Enqueue adds an item at the back of the queue
+func (q *LockFreeQueue) Enqueue(item interface{}) {
+	node := &lockFreeNode{value: unsafe.Pointer(&item)}
+	for {
+		tail := loadNode(&q.tail)
+		next := loadNode(&tail.next)
+		if tail != loadNode(&q.tail) {
+			continue
+		}
+
+		if next != nil {
+			// tail is falling behind, help to advance it
+			casNode(&q.tail, tail, next)
+			continue
+		}
+		if casNode(&tail.next, nil, node) {
+			casNode(&q.tail, tail, node)
+			atomic.AddInt64(&q.size, 1)
+			return
+		}
+	}
+}
+
+// Dequeue removes and returns the front queue item, or nil if the queue is empty
+func (q *LockFreeQueue) Dequeue() interface{} {
+	for {
+		head := loadNode(&q.head)
+		tail := loadNode(&q.tail)
+		next := loadNode(&head.next)
+		if head != loadNode(&q.head) {
+			continue
+		}
+
+		if next == nil {
+			return nil
+		}
+		if head == tail {
+			// tail is falling behind, help to advance it
+			casNode(&q.tail, tail, next)
+			continue
+		}
+
+		value, _ := next.loadValue()
+		if casNode(&q.head, head, next) {
+			// next is the new dummy node, the value is cleared only after
+			// head is moved, so no other Dequeue can return it
+			atomic.StorePointer(&next.value, nil)
+			atomic.AddInt64(&q.size, -1)
+			return value
+		}
+	}
+}
+
+// Head returns the front queue item, or nil if the queue is empty
+func (q *LockFreeQueue) Head() interface{} {
+	for {
+		head := loadNode(&q.head)
+		next := loadNode(&head.next)
+		if next == nil {
+			return nil
+		}
+		// the value is read before checking head, a cleared value means
+		// head has moved
+		value, ok := next.loadValue()
+		if ok && head == loadNode(&q.head) {
+			return value
+		}
+	}
+}
+
+// Size returns the number of items, it is a snapshot which may be stale
+// under concurrent usage
+func (q *LockFreeQueue) Size() int {
+	if size := atomic.LoadInt64(&q.size); size > 0 {
+		return int(size)
+	}
+	return 0
+}
+
+// IsEmpty checks if the queue is empty
+func (q *LockFreeQueue) IsEmpty() bool {
+	return loadNode(&loadNode(&q.head).next) == nil
+}
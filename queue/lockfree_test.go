@@ -0,0 +1,165 @@
+package queue
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestLockFreeQueue(t *testing.T) {
+	queue := NewLockFreeQueue()
+	if queue.Dequeue() != nil || queue.Head() != nil || !queue.IsEmpty() {
+		t.Error("Empty LockFreeQueue not return nil")
+	}
+
+	for i := 0; i < 100; i++ {
+		queue.Enqueue(i)
+	}
+	if queue.Size() != 100 || queue.Head() != 0 || queue.IsEmpty() {
+		t.Errorf("Got %v, %v expected %v, %v for size and head", queue.Size(), queue.Head(), 100, 0)
+	}
+	for i := 0; i < 100; i++ {
+		if item := queue.Dequeue(); item != i {
+			t.Errorf("Got %v expected %v for dequeue", item, i)
+		}
+	}
+	if queue.Size() != 0 || queue.Dequeue() != nil {
+		t.Error("LockFreeQueue should be empty")
+	}
+}
+
+func TestLockFreeQueueReleasesValue(t *testing.T) {
+	queue := NewLockFreeQueue()
+	queue.Enqueue(1)
+	queue.Enqueue(2)
+	queue.Dequeue()
+
+	dummy := loadNode(&queue.head)
+	if _, ok := dummy.loadValue(); ok {
+		t.Errorf("Got value retained expected cleared for dummy node")
+	}
+	if v := queue.Head(); v != 2 {
+		t.Errorf("Got %v expected %v for Head", v, 2)
+	}
+}
+
+func TestLockFreeQueueConcurrent(t *testing.T) {
+	queue := NewLockFreeQueue()
+	producers, consumers, count := 8, 8, 2000
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < count; i++ {
+				queue.Enqueue([2]int{p, i})
+			}
+		}(p)
+	}
+
+	results := make(chan [][2]int, consumers)
+	var received int64
+	var mu sync.Mutex
+	for c := 0; c < consumers; c++ {
+		go func() {
+			items := [][2]int{}
+			for {
+				mu.Lock()
+				done := received == int64(producers*count)
+				mu.Unlock()
+				if done {
+					break
+				}
+
+				item := queue.Dequeue()
+				if item == nil {
+					runtime.Gosched()
+					continue
+				}
+				items = append(items, item.([2]int))
+				mu.Lock()
+				received++
+				mu.Unlock()
+			}
+			results <- items
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[[2]int]bool)
+	for c := 0; c < consumers; c++ {
+		last := make([]int, producers)
+		for i := range last {
+			last[i] = -1
+		}
+		for _, item := range <-results {
+			if seen[item] {
+				t.Fatalf("Item %v is dequeued twice", item)
+			}
+			seen[item] = true
+			// items of one producer are dequeued in order by every consumer
+			if item[1] <= last[item[0]] {
+				t.Fatalf("Item %v is dequeued after %v", item, last[item[0]])
+			}
+			last[item[0]] = item[1]
+		}
+	}
+	if len(seen) != producers*count || queue.Size() != 0 {
+		t.Errorf("Got %v expected %v for dequeued items", len(seen), producers*count)
+	}
+}
+
+type benchmarkQueue interface {
+	Enqueue(item interface{})
+	Dequeue() interface{}
+}
+
+// benchmarkContention runs enqueue and dequeue pairs by 32 goroutines per CPU.
+func benchmarkContention(b *testing.B, queue benchmarkQueue) {
+	b.SetParallelism(32)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			queue.Enqueue(1)
+			queue.Dequeue()
+		}
+	})
+}
+
+func BenchmarkQueueContention(b *testing.B) {
+	benchmarkContention(b, NewQueue())
+}
+
+func BenchmarkLockFreeQueueContention(b *testing.B) {
+	benchmarkContention(b, NewLockFreeQueue())
+}
+
+// benchmarkProducerConsumer runs half producers and half consumers.
+func benchmarkProducerConsumer(b *testing.B, queue benchmarkQueue) {
+	var wg sync.WaitGroup
+	goroutines := 32
+	b.ResetTimer()
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := g; i < b.N; i += goroutines {
+				if g%2 == 0 {
+					queue.Enqueue(i)
+				} else {
+					queue.Dequeue()
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func BenchmarkQueueProducerConsumer(b *testing.B) {
+	benchmarkProducerConsumer(b, NewQueue())
+}
+
+func BenchmarkLockFreeQueueProducerConsumer(b *testing.B) {
+	benchmarkProducerConsumer(b, NewLockFreeQueue())
+}
@@ -0,0 +1,107 @@
+package queue
+
+import (
+	"sync"
+
+	"github.com/aiden0z/kit/base"
+)
+
+// MonotonicDeque maintains the maximum and the minimum of a sliding window
+// over a stream. Every item is pushed with its position in the stream, e.g.
+// a sequence number or a timestamp, and Evict drops the items out of the window.
+// Items which can never be the extremes again are dropped on Push, so that
+// Push takes amortized O(1) time and Max and Min take O(1) time.
+//
+// every operations over a MonotonicDeque are synchronized and
+// safe for concurrent usage.
+type MonotonicDeque struct {
+	sync.RWMutex
+	// positions are increasing and values are decreasing from the front to the back
+	maxDeque *Deque
+	// positions are increasing and values are increasing from the front to the back
+	minDeque *Deque
+}
+
+type monotonicEntry struct {
+	position int64
+	value    base.Comparable
+}
+
+// NewMonotonicDeque creates a MonotonicDeque.
+func NewMonotonicDeque() *MonotonicDeque {
+	return &MonotonicDeque{
+		maxDeque: NewDeque(),
+		minDeque: NewDeque(),
+	}
+}
+
+// Push adds the value at position into the window, positions must be pushed
+// in non-decreasing order.
+func (s *MonotonicDeque) Push(position int64, value base.Comparable) {
+	s.Lock()
+	defer s.Unlock()
+
+	entry := &monotonicEntry{position: position, value: value}
+	for last := s.maxDeque.Last(); last != nil && last.(*monotonicEntry).value.CompareTo(value) <= 0; last = s.maxDeque.Last() {
+		s.maxDeque.Pop()
+	}
+	s.maxDeque.Append(entry)
+
+	for last := s.minDeque.Last(); last != nil && last.(*monotonicEntry).value.CompareTo(value) >= 0; last = s.minDeque.Last() {
+		s.minDeque.Pop()
+	}
+	s.minDeque.Append(entry)
+}
+
+// Evict removes the values at positions less than olderThan from the window.
+func (s *MonotonicDeque) Evict(olderThan int64) {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, deque := range []*Deque{s.maxDeque, s.minDeque} {
+		for first := deque.First(); first != nil && first.(*monotonicEntry).position < olderThan; first = deque.First() {
+			deque.Shift()
+		}
+	}
+}
+
+// Max returns the maximum value of the window, or nil if the window is empty.
+// The latest one is returned if multiple values are equal.
+func (s *MonotonicDeque) Max() base.Comparable {
+	s.RLock()
+	defer s.RUnlock()
+
+	if first := s.maxDeque.First(); first != nil {
+		return first.(*monotonicEntry).value
+	}
+	return nil
+}
+
+// Min returns the minimum value of the window, or nil if the window is empty.
+// The latest one is returned if multiple values are equal.
+func (s *MonotonicDeque) Min() base.Comparable {
+	s.RLock()
+	defer s.RUnlock()
+
+	if first := s.minDeque.First(); first != nil {
+		return first.(*monotonicEntry).value
+	}
+	return nil
+}
+
+// IsEmpty checks if the window is empty
+func (s *MonotonicDeque) IsEmpty() bool {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.maxDeque.IsEmpty()
+}
+
+// Clear removes all values
+func (s *MonotonicDeque) Clear() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.maxDeque = NewDeque()
+	s.minDeque = NewDeque()
+}
@@ -0,0 +1,52 @@
+package queue
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/aiden0z/kit/base"
+)
+
+func TestMonotonicDeque(t *testing.T) {
+	deque := NewMonotonicDeque()
+	if deque.Max() != nil || deque.Min() != nil || !deque.IsEmpty() {
+		t.Error("Empty MonotonicDeque not return nil")
+	}
+
+	r := rand.New(rand.NewSource(1))
+	window := 10
+	values := make([]int, 500)
+	for i := range values {
+		values[i] = r.Intn(50)
+		deque.Push(int64(i), base.Int(values[i]))
+		deque.Evict(int64(i - window + 1))
+
+		lo := i - window + 1
+		if lo < 0 {
+			lo = 0
+		}
+		min, max := values[lo], values[lo]
+		for _, v := range values[lo : i+1] {
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+		}
+		if deque.Min() != base.Int(min) || deque.Max() != base.Int(max) {
+			t.Fatalf("Got %v, %v expected %v, %v for window ends at %d", deque.Min(), deque.Max(), min, max, i)
+		}
+	}
+
+	deque.Evict(int64(len(values)))
+	if !deque.IsEmpty() || deque.Max() != nil {
+		t.Error("MonotonicDeque Evict all error")
+	}
+
+	deque.Push(1, base.Int(1))
+	deque.Clear()
+	if !deque.IsEmpty() {
+		t.Error("MonotonicDeque Clear error")
+	}
+}
@@ -0,0 +1,135 @@
+package queue
+
+import (
+	"sync"
+
+	"github.com/aiden0z/kit/stack"
+)
+
+// stream is a lazy list, the cell is computed by thunk on the first force
+// and memoized, so that the older versions of a persistent queue share the
+// evaluation.
+type stream struct {
+	once  sync.Once
+	thunk func() *cell
+	cell  *cell
+}
+
+// cell is a node of stream, a nil cell is the end of stream.
+type cell struct {
+	head interface{}
+	tail *stream
+}
+
+var emptyStream = evaluated(nil)
+
+func lazy(thunk func() *cell) *stream {
+	return &stream{thunk: thunk}
+}
+
+func evaluated(c *cell) *stream {
+	s := &stream{cell: c}
+	s.once.Do(func() {})
+	return s
+}
+
+func (s *stream) force() *cell {
+	s.once.Do(func() {
+		s.cell = s.thunk()
+		s.thunk = nil
+	})
+	return s.cell
+}
+
+// rotate returns front ++ reverse(rear) ++ accumulator lazily, where rear has
+// one item more than front. Every force evaluates one step.
+func rotate(front *stream, rear *stack.PersistentStack, accumulator *stream) *stream {
+	return lazy(func() *cell {
+		item, rest := rear.Pop()
+		c := front.force()
+		if c == nil {
+			return &cell{head: item, tail: accumulator}
+		}
+		return &cell{head: c.head, tail: rotate(c.tail, rest, evaluated(&cell{head: item, tail: accumulator}))}
+	})
+}
+
+// PersistentQueue is an immutable FIFO queue, Enqueue and Dequeue return new
+// versions in O(1) worst case time without mutating the older versions.
+// It is the real-time queue of Okasaki, the front is a lazy stream, the rear
+// is a persistent stack, and the rear is rotated onto the front incrementally
+// by a schedule before it becomes longer than the front.
+// It is safe for concurrent usage.
+//
+// Reference:
+// - Purely Functional Data Structures, Chris Okasaki, 7.2
+type PersistentQueue struct {
+	front *stream
+	rear  *stack.PersistentStack
+	// schedule is the unevaluated suffix of front, |schedule| = |front| - |rear|
+	schedule *stream
+	size     int
+}
+
+// NewPersistentQueue creates an empty PersistentQueue.
+func NewPersistentQueue() *PersistentQueue {
+	return &PersistentQueue{
+		front:    emptyStream,
+		rear:     stack.NewPersistentStack(),
+		schedule: emptyStream,
+	}
+}
+
+// exec evaluates one step of the schedule, or starts a rotation when the
+// rear becomes longer than the front.
+func exec(front *stream, rear *stack.PersistentStack, schedule *stream, size int) *PersistentQueue {
+	if c := schedule.force(); c != nil {
+		return &PersistentQueue{front: front, rear: rear, schedule: c.tail, size: size}
+	}
+
+	front = rotate(front, rear, emptyStream)
+	return &PersistentQueue{front: front, rear: stack.NewPersistentStack(), schedule: front, size: size}
+}
+
+// Enqueue returns a new version with item at the back of the queue
+func (q *PersistentQueue) Enqueue(item interface{}) *PersistentQueue {
+	return exec(q.front, q.rear.Push(item), q.schedule, q.size+1)
+}
+
+// Dequeue returns the front item and the version without it, if queue is
+// empty, will return nil and the queue itself
+func (q *PersistentQueue) Dequeue() (interface{}, *PersistentQueue) {
+	c := q.front.force()
+	if c == nil {
+		return nil, q
+	}
+	return c.head, exec(c.tail, q.rear, q.schedule, q.size-1)
+}
+
+// Head returns the front queue item
+func (q *PersistentQueue) Head() interface{} {
+	if c := q.front.force(); c != nil {
+		return c.head
+	}
+	return nil
+}
+
+// Size returns the number of items
+func (q *PersistentQueue) Size() int {
+	return q.size
+}
+
+// IsEmpty checks if the queue is empty
+func (q *PersistentQueue) IsEmpty() bool {
+	return q.size == 0
+}
+
+// Values returns the items from the front to the back
+func (q *PersistentQueue) Values() []interface{} {
+	values := make([]interface{}, 0, q.size)
+	for c := q.front.force(); c != nil; c = c.tail.force() {
+		values = append(values, c.head)
+	}
+	// the bottom of the rear is the earliest enqueued
+	return append(values, q.rear.Values()...)
+}
@@ -0,0 +1,101 @@
+package queue
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestPersistentQueue(t *testing.T) {
+	empty := NewPersistentQueue()
+	if item, q := empty.Dequeue(); item != nil || q != empty || empty.Head() != nil || !empty.IsEmpty() {
+		t.Error("Dequeue from empty persistent queue error")
+	}
+
+	q1 := empty.Enqueue(1).Enqueue(2).Enqueue(3)
+	item, q2 := q1.Dequeue()
+	if item != 1 || q2.Head() != 2 || q1.Head() != 1 {
+		t.Errorf("Got %v expected %v for dequeue", item, 1)
+	}
+
+	q3 := q2.Enqueue(4)
+	q4 := q2.Enqueue(5)
+	if v := q3.Values(); len(v) != 3 || v[2] != 4 {
+		t.Errorf("Got %v expected %v for values", v, []int{2, 3, 4})
+	}
+	if v := q4.Values(); len(v) != 3 || v[2] != 5 {
+		t.Errorf("Got %v expected %v for values", v, []int{2, 3, 5})
+	}
+	if v := q1.Values(); len(v) != 3 || v[0] != 1 || v[2] != 3 {
+		t.Errorf("Got %v expected %v for values of old version", v, []int{1, 2, 3})
+	}
+}
+
+type persistentVersion struct {
+	queue *PersistentQueue
+	items []int
+}
+
+func TestPersistentQueueRandomVersions(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	versions := []persistentVersion{{queue: NewPersistentQueue()}}
+
+	for i := 0; i < 5000; i++ {
+		// operate on a random older version
+		v := versions[r.Intn(len(versions))]
+		var next persistentVersion
+		if len(v.items) > 0 && r.Intn(3) == 0 {
+			item, q := v.queue.Dequeue()
+			if item != v.items[0] {
+				t.Fatalf("Got %v expected %v for dequeue", item, v.items[0])
+			}
+			next = persistentVersion{queue: q, items: v.items[1:]}
+		} else {
+			items := append(append([]int{}, v.items...), i)
+			next = persistentVersion{queue: v.queue.Enqueue(i), items: items}
+		}
+
+		if next.queue.Size() != len(next.items) {
+			t.Fatalf("Got %v expected %v for size", next.queue.Size(), len(next.items))
+		}
+		versions = append(versions, next)
+	}
+
+	for _, v := range versions {
+		values := v.queue.Values()
+		if len(values) != len(v.items) {
+			t.Fatalf("Got %v expected %v for values", values, v.items)
+		}
+		for i := range values {
+			if values[i] != v.items[i] {
+				t.Fatalf("Got %v expected %v for values", values, v.items)
+			}
+		}
+	}
+}
+
+func TestPersistentQueueConcurrentForks(t *testing.T) {
+	q := NewPersistentQueue()
+	for i := 0; i < 100; i++ {
+		q = q.Enqueue(i)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			fork := q
+			for i := 0; i < 100; i++ {
+				var item interface{}
+				item, fork = fork.Dequeue()
+				if item != i {
+					t.Errorf("Got %v expected %v for dequeue in goroutine %d", item, i, g)
+					return
+				}
+				fork = fork.Enqueue(i)
+			}
+		}(g)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,209 @@
+package queue
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// InvalidReceiptErr is returned when the receipt is not of an in-flight
+// delivery, it is acked, nacked or its visibility timeout has passed.
+var InvalidReceiptErr = errors.New("invalid receipt")
+
+// Receipt identifies a delivery of ReliableQueue, every delivery of an item
+// has a new receipt.
+type Receipt uint64
+
+// Delivery is an item dequeued from a ReliableQueue.
+type Delivery struct {
+	Item    interface{}
+	Receipt Receipt
+	// Deliveries is the number of times the item is delivered, including this one
+	Deliveries int
+}
+
+type message struct {
+	item       interface{}
+	deliveries int
+}
+
+type inflight struct {
+	message  *message
+	deadline time.Time
+}
+
+// ReliableQueue is a FIFO queue for at least once processing. A dequeued
+// item is invisible until it is acked, nacked, or its visibility timeout
+// passes, then it returns to the back of the queue. After maxDeliveries
+// deliveries without ack the item is moved to the dead-letter deque instead.
+//
+// The timeouts are checked by the operations of the queue, there is no
+// background goroutine.
+//
+// every operations over a ReliableQueue are synchronized and
+// safe for concurrent usage.
+type ReliableQueue struct {
+	mu            sync.Mutex
+	clock         Clock
+	visibility    time.Duration
+	maxDeliveries int
+	ready         *Queue
+	inflight      map[Receipt]*inflight
+	// timeouts holds the receipts by their deadlines, the acked ones are
+	// skipped when they expire
+	timeouts    *DelayQueue
+	deadLetters *Deque
+	receipt     Receipt
+}
+
+// NewReliableQueue creates a ReliableQueue with the visibility timeout of
+// deliveries, items are dead-lettered after maxDeliveries deliveries, or
+// never if it is not positive. SystemClock is used if clock is nil.
+func NewReliableQueue(visibility time.Duration, maxDeliveries int, clock Clock) *ReliableQueue {
+	if clock == nil {
+		clock = SystemClock
+	}
+	return &ReliableQueue{
+		clock:         clock,
+		visibility:    visibility,
+		maxDeliveries: maxDeliveries,
+		ready:         NewQueue(),
+		inflight:      make(map[Receipt]*inflight),
+		timeouts:      NewDelayQueue(clock),
+		deadLetters:   NewDeque(),
+	}
+}
+
+// Enqueue adds an item at the back of the queue
+func (q *ReliableQueue) Enqueue(item interface{}) {
+	q.ready.Enqueue(&message{item: item})
+}
+
+// requeue returns a message of a failed delivery to the queue, or to the
+// dead-letter deque after too many deliveries.
+func (q *ReliableQueue) requeue(m *message) {
+	if q.maxDeliveries > 0 && m.deliveries >= q.maxDeliveries {
+		q.deadLetters.Append(m.item)
+		return
+	}
+	q.ready.Enqueue(m)
+}
+
+// expire requeues the deliveries whose visibility timeout has passed.
+func (q *ReliableQueue) expire() {
+	for {
+		item, ok := q.timeouts.Poll()
+		if !ok {
+			return
+		}
+		// read the clock after Poll, which reads it too, so a deadline
+		// passed for Poll is passed here as well
+		now := q.clock.Now()
+		receipt := item.(Receipt)
+		// the receipt is acked, or extended to a later deadline
+		if d, found := q.inflight[receipt]; found && !d.deadline.After(now) {
+			delete(q.inflight, receipt)
+			q.requeue(d.message)
+		}
+	}
+}
+
+// Dequeue delivers the front queue item, or returns nil if no item is
+// visible. The item must be acked before the visibility timeout passes.
+func (q *ReliableQueue) Dequeue() *Delivery {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.expire()
+	item := q.ready.Dequeue()
+	if item == nil {
+		return nil
+	}
+
+	m := item.(*message)
+	m.deliveries++
+	q.receipt++
+	deadline := q.clock.Now().Add(q.visibility)
+	q.inflight[q.receipt] = &inflight{message: m, deadline: deadline}
+	q.timeouts.Enqueue(q.receipt, deadline)
+
+	return &Delivery{Item: m.item, Receipt: q.receipt, Deliveries: m.deliveries}
+}
+
+// Ack deletes the delivered item.
+func (q *ReliableQueue) Ack(receipt Receipt) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.expire()
+	if _, found := q.inflight[receipt]; !found {
+		return InvalidReceiptErr
+	}
+	delete(q.inflight, receipt)
+	return nil
+}
+
+// Nack returns the delivered item to the back of the queue immediately, or
+// to the dead-letter deque after too many deliveries.
+func (q *ReliableQueue) Nack(receipt Receipt) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.expire()
+	d, found := q.inflight[receipt]
+	if !found {
+		return InvalidReceiptErr
+	}
+	delete(q.inflight, receipt)
+	q.requeue(d.message)
+	return nil
+}
+
+// Extend resets the visibility timeout of the delivery to timeout from now,
+// for the items take longer to process.
+func (q *ReliableQueue) Extend(receipt Receipt, timeout time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.expire()
+	d, found := q.inflight[receipt]
+	if !found {
+		return InvalidReceiptErr
+	}
+	d.deadline = q.clock.Now().Add(timeout)
+	q.timeouts.Enqueue(receipt, d.deadline)
+	return nil
+}
+
+// Size returns the number of visible items
+func (q *ReliableQueue) Size() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.expire()
+	return q.ready.Size()
+}
+
+// InFlight returns the number of delivered items not acked yet
+func (q *ReliableQueue) InFlight() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.expire()
+	return len(q.inflight)
+}
+
+// IsEmpty checks if there is neither visible nor in-flight item
+func (q *ReliableQueue) IsEmpty() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.expire()
+	return q.ready.IsEmpty() && len(q.inflight) == 0
+}
+
+// DeadLetters returns the deque of the items failed maxDeliveries
+// deliveries, in the order they failed.
+func (q *ReliableQueue) DeadLetters() *Deque {
+	return q.deadLetters
+}
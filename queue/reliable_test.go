@@ -0,0 +1,172 @@
+package queue
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReliableQueueAck(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	q := NewReliableQueue(time.Minute, 0, clock)
+	q.Enqueue(1)
+	q.Enqueue(2)
+
+	d := q.Dequeue()
+	if d == nil || d.Item != 1 || d.Deliveries != 1 {
+		t.Fatalf("Got %v expected delivery of %v", d, 1)
+	}
+	if q.Size() != 1 || q.InFlight() != 1 || q.IsEmpty() {
+		t.Errorf("Got %v, %v expected %v, %v for size and in-flight", q.Size(), q.InFlight(), 1, 1)
+	}
+	if err := q.Ack(d.Receipt); err != nil {
+		t.Errorf("Ack error %v", err)
+	}
+	if err := q.Ack(d.Receipt); err != InvalidReceiptErr {
+		t.Errorf("Got %v expected %v for acked receipt", err, InvalidReceiptErr)
+	}
+
+	// acked items do not return
+	clock.Advance(2 * time.Minute)
+	d = q.Dequeue()
+	if d == nil || d.Item != 2 {
+		t.Fatalf("Got %v expected delivery of %v", d, 2)
+	}
+	q.Ack(d.Receipt)
+	if d := q.Dequeue(); d != nil || !q.IsEmpty() {
+		t.Errorf("Got %v expected empty queue", d)
+	}
+}
+
+func TestReliableQueueTimeout(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	q := NewReliableQueue(time.Minute, 0, clock)
+	q.Enqueue(1)
+	q.Enqueue(2)
+
+	first := q.Dequeue()
+	clock.Advance(59 * time.Second)
+	if d := q.Dequeue(); d == nil || d.Item != 2 {
+		t.Fatalf("Got %v expected delivery of %v", d, 2)
+	}
+	if d := q.Dequeue(); d != nil {
+		t.Errorf("Got %v expected no visible item before timeout", d)
+	}
+
+	// the first item is visible again at the back of the queue
+	clock.Advance(time.Second)
+	d := q.Dequeue()
+	if d == nil || d.Item != 1 || d.Deliveries != 2 || d.Receipt == first.Receipt {
+		t.Fatalf("Got %v expected redelivery of %v", d, 1)
+	}
+	if err := q.Ack(first.Receipt); err != InvalidReceiptErr {
+		t.Errorf("Got %v expected %v for expired receipt", err, InvalidReceiptErr)
+	}
+
+	// extend the visibility timeout
+	if err := q.Extend(d.Receipt, 5*time.Minute); err != nil {
+		t.Errorf("Extend error %v", err)
+	}
+	clock.Advance(time.Minute)
+	// the second item times out, the first is extended
+	if q.Size() != 1 || q.InFlight() != 1 {
+		t.Errorf("Got %v, %v expected %v, %v for size and in-flight", q.Size(), q.InFlight(), 1, 1)
+	}
+	if err := q.Ack(d.Receipt); err != nil {
+		t.Errorf("Ack error %v", err)
+	}
+}
+
+// tickingClock advances every time it is read.
+type tickingClock struct {
+	*ManualClock
+	step time.Duration
+}
+
+func (c *tickingClock) Now() time.Time {
+	c.Advance(c.step)
+	return c.ManualClock.Now()
+}
+
+func TestReliableQueueTickingClock(t *testing.T) {
+	clock := &tickingClock{ManualClock: NewManualClock(time.Unix(0, 0)), step: time.Millisecond}
+	q := NewReliableQueue(1500*time.Microsecond, 0, clock)
+	q.Enqueue(1)
+
+	first := q.Dequeue()
+	for i := 0; i < 10; i++ {
+		if d := q.Dequeue(); d != nil {
+			if d.Item != 1 || d.Deliveries != 2 || d.Receipt == first.Receipt {
+				t.Errorf("Got %v expected redelivery of %v", d, 1)
+			}
+			return
+		}
+	}
+	t.Errorf("Got %v, %v expected %v, %v for size and in-flight", q.Size(), q.InFlight(), 1, 0)
+}
+
+func TestReliableQueueDeadLetters(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	q := NewReliableQueue(time.Minute, 3, clock)
+	q.Enqueue("poison")
+	q.Enqueue("ok")
+
+	d := q.Dequeue()
+	if err := q.Nack(d.Receipt); err != nil {
+		t.Errorf("Nack error %v", err)
+	}
+	if err := q.Nack(d.Receipt); err != InvalidReceiptErr {
+		t.Errorf("Got %v expected %v for nacked receipt", err, InvalidReceiptErr)
+	}
+	if d := q.Dequeue(); d.Item != "ok" {
+		t.Errorf("Got %v expected %v, nacked item at the back", d.Item, "ok")
+	} else {
+		q.Ack(d.Receipt)
+	}
+
+	d = q.Dequeue()
+	q.Nack(d.Receipt)
+	d = q.Dequeue()
+	if d.Item != "poison" || d.Deliveries != 3 {
+		t.Errorf("Got %v, %v expected %v, %v", d.Item, d.Deliveries, "poison", 3)
+	}
+	// the last delivery times out
+	clock.Advance(time.Minute)
+	if !q.IsEmpty() || q.DeadLetters().Size() != 1 || q.DeadLetters().First() != "poison" {
+		t.Errorf("Got %v expected %v in dead letters", q.DeadLetters().First(), "poison")
+	}
+}
+
+func TestReliableQueueConcurrent(t *testing.T) {
+	q := NewReliableQueue(time.Minute, 0, nil)
+	sampleSize := 1000
+	for i := 0; i < sampleSize; i++ {
+		q.Enqueue(i)
+	}
+
+	var mu sync.Mutex
+	seen := make(map[interface{}]bool)
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for d := q.Dequeue(); d != nil; d = q.Dequeue() {
+				// nack every other first delivery
+				if d.Deliveries == 1 && d.Item.(int)%2 == 0 {
+					q.Nack(d.Receipt)
+					continue
+				}
+				mu.Lock()
+				seen[d.Item] = true
+				mu.Unlock()
+				q.Ack(d.Receipt)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != sampleSize || !q.IsEmpty() {
+		t.Errorf("Got %v expected %v acked items", len(seen), sampleSize)
+	}
+}
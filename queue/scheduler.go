@@ -0,0 +1,235 @@
+package queue
+
+import (
+	"errors"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// SchedulerClosedErr is returned when submitting tasks to a closed Scheduler.
+var SchedulerClosedErr = errors.New("scheduler closed")
+
+// Task is a unit of work run by a worker of Scheduler, it may spawn subtasks
+// by the worker.
+type Task func(w *Worker)
+
+// Scheduler is a reference work-stealing task scheduler. Every worker owns a
+// WorkStealingDeque, subtasks spawned by a task are pushed to the bottom of
+// the deque of its worker and popped in LIFO order for locality, idle workers
+// steal from the top of the deques of other workers. Tasks submitted from
+// outside are queued in a shared LockFreeQueue. Workers without any task to
+// run or steal are parked until a task is queued, they use no CPU while the
+// scheduler is idle.
+type Scheduler struct {
+	workers  []*Worker
+	injector *LockFreeQueue
+
+	// pending is the number of tasks submitted or spawned but not finished
+	pending int64
+	// epoch is increased when a task is queued, so that a worker parks only
+	// if no task is queued since it looked for one
+	epoch    uint64
+	sleeping int32
+	mu       sync.Mutex
+	idle     *sync.Cond
+	// wake is signaled to unpark a worker when a task is queued
+	wake *sync.Cond
+
+	// closed is guarded by mu, so no task is submitted after Close starts
+	// waiting for the pending tasks
+	closed  bool
+	stop    chan struct{}
+	stopped sync.WaitGroup
+}
+
+// Worker runs tasks of a Scheduler.
+type Worker struct {
+	id        int
+	scheduler *Scheduler
+	deque     *WorkStealingDeque
+	random    *rand.Rand
+	steals    int64
+}
+
+// NewScheduler creates a Scheduler and starts the workers, it starts
+// runtime.GOMAXPROCS(0) workers if workers is not positive.
+func NewScheduler(workers int) *Scheduler {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	s := &Scheduler{
+		injector: NewLockFreeQueue(),
+		stop:     make(chan struct{}),
+	}
+	s.idle = sync.NewCond(&s.mu)
+	s.wake = sync.NewCond(&s.mu)
+	for i := 0; i < workers; i++ {
+		s.workers = append(s.workers, &Worker{
+			id:        i,
+			scheduler: s,
+			deque:     NewWorkStealingDeque(),
+			random:    rand.New(rand.NewSource(int64(i))),
+		})
+	}
+
+	s.stopped.Add(workers)
+	for _, w := range s.workers {
+		go w.run()
+	}
+	return s
+}
+
+// Submit queues the task, it returns SchedulerClosedErr if the scheduler is closed.
+func (s *Scheduler) Submit(task Task) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return SchedulerClosedErr
+	}
+	atomic.AddInt64(&s.pending, 1)
+	s.injector.Enqueue(task)
+	s.mu.Unlock()
+
+	s.notify()
+	return nil
+}
+
+// notify unparks a worker after a task is queued.
+func (s *Scheduler) notify() {
+	atomic.AddUint64(&s.epoch, 1)
+	if atomic.LoadInt32(&s.sleeping) > 0 {
+		s.mu.Lock()
+		s.wake.Signal()
+		s.mu.Unlock()
+	}
+}
+
+// park blocks the worker until a task is queued after seen epoch, it returns
+// false if the scheduler is stopped.
+func (s *Scheduler) park(seen uint64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// notify reads sleeping after increasing epoch, so either it signals
+	// or the new epoch is seen here
+	atomic.AddInt32(&s.sleeping, 1)
+	defer atomic.AddInt32(&s.sleeping, -1)
+	for atomic.LoadUint64(&s.epoch) == seen {
+		select {
+		case <-s.stop:
+			return false
+		default:
+		}
+		s.wake.Wait()
+	}
+	return true
+}
+
+func (s *Scheduler) finish() {
+	if atomic.AddInt64(&s.pending, -1) == 0 {
+		s.mu.Lock()
+		s.idle.Broadcast()
+		s.mu.Unlock()
+	}
+}
+
+// Wait blocks until all submitted tasks and their subtasks are finished.
+func (s *Scheduler) Wait() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.wait()
+}
+
+// wait must be called with mu held.
+func (s *Scheduler) wait() {
+	for atomic.LoadInt64(&s.pending) > 0 {
+		s.idle.Wait()
+	}
+}
+
+// Close rejects new tasks, waits for the pending tasks and stops the workers.
+func (s *Scheduler) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	s.wait()
+	s.mu.Unlock()
+
+	close(s.stop)
+	s.mu.Lock()
+	s.wake.Broadcast()
+	s.mu.Unlock()
+	s.stopped.Wait()
+}
+
+// Steals returns the number of tasks stolen between workers.
+func (s *Scheduler) Steals() int64 {
+	var steals int64
+	for _, w := range s.workers {
+		steals += atomic.LoadInt64(&w.steals)
+	}
+	return steals
+}
+
+// ID returns the index of the worker.
+func (w *Worker) ID() int {
+	return w.id
+}
+
+// Spawn pushes a subtask to the deque of the worker, it must be called by
+// the task running on the worker.
+func (w *Worker) Spawn(task Task) {
+	atomic.AddInt64(&w.scheduler.pending, 1)
+	w.deque.PushBottom(task)
+	w.scheduler.notify()
+}
+
+// find returns the next task from the own deque, the shared queue, or the
+// deque of a random victim.
+func (w *Worker) find() Task {
+	if task, ok := w.deque.PopBottom(); ok {
+		return task.(Task)
+	}
+	if task := w.scheduler.injector.Dequeue(); task != nil {
+		return task.(Task)
+	}
+
+	workers := w.scheduler.workers
+	start := w.random.Intn(len(workers))
+	for i := range workers {
+		victim := workers[(start+i)%len(workers)]
+		if victim == w {
+			continue
+		}
+		if task, ok := victim.deque.Steal(); ok {
+			atomic.AddInt64(&w.steals, 1)
+			return task.(Task)
+		}
+	}
+	return nil
+}
+
+func (w *Worker) run() {
+	defer w.scheduler.stopped.Done()
+
+	for {
+		seen := atomic.LoadUint64(&w.scheduler.epoch)
+		if task := w.find(); task != nil {
+			task(w)
+			w.scheduler.finish()
+			continue
+		}
+
+		// park when there is no work
+		if !w.scheduler.park(seen) {
+			return
+		}
+	}
+}
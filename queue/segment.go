@@ -0,0 +1,273 @@
+package queue
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Files of a DiskQueue directory.
+// Items are appended to segment files as records, a record is the length
+// and the CRC-32 of the data in little endian, then the data. A segment is
+// named by the sequence number of its first record, the last segment is
+// written and the others are read only. The meta file keeps the head, it is
+// replaced by renaming a temporary file so it is never partially written.
+
+const (
+	segmentExt       = ".seg"
+	tmpExt           = ".tmp"
+	metaFile         = "meta"
+	recordHeaderSize = 8
+	metaSize         = 28
+)
+
+type segment struct {
+	start uint64
+	size  int64
+}
+
+// diskPosition is the position of a record in the segments.
+type diskPosition struct {
+	segment uint64
+	offset  int64
+}
+
+func segmentPath(dir string, start uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", start, segmentExt))
+}
+
+// listSegments returns the segments of dir in ascending order and removes
+// the temporary files left by a crash.
+func listSegments(dir string) ([]*segment, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := []*segment{}
+	for _, file := range files {
+		name := file.Name()
+		if strings.HasSuffix(name, tmpExt) {
+			if err := os.Remove(filepath.Join(dir, name)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+		start, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, &segment{start: start, size: file.Size()})
+	}
+
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].start < segments[j].start
+	})
+	return segments, nil
+}
+
+func appendRecord(dst []byte, data []byte) []byte {
+	var header [recordHeaderSize]byte
+	binary.LittleEndian.PutUint32(header[:4], uint32(len(data)))
+	binary.LittleEndian.PutUint32(header[4:], crc32.ChecksumIEEE(data))
+	dst = append(dst, header[:]...)
+	return append(dst, data...)
+}
+
+// readRecord reads the record at offset of a segment of size, it returns the
+// data and the offset of the next record. io.EOF is returned at the end of
+// the segment, and CorruptedQueueErr for a partial or corrupted record.
+func readRecord(r io.ReaderAt, offset, size int64, withData bool) ([]byte, int64, error) {
+	if offset >= size {
+		return nil, offset, io.EOF
+	}
+	if offset+recordHeaderSize > size {
+		return nil, offset, CorruptedQueueErr
+	}
+
+	var header [recordHeaderSize]byte
+	if _, err := r.ReadAt(header[:], offset); err != nil {
+		return nil, offset, err
+	}
+	length := int64(binary.LittleEndian.Uint32(header[:4]))
+	next := offset + recordHeaderSize + length
+	if next > size {
+		return nil, offset, CorruptedQueueErr
+	}
+	if !withData {
+		return nil, next, nil
+	}
+
+	data := make([]byte, length)
+	if _, err := r.ReadAt(data, offset+recordHeaderSize); err != nil {
+		return nil, offset, err
+	}
+	if crc32.ChecksumIEEE(data) != binary.LittleEndian.Uint32(header[4:]) {
+		return nil, offset, CorruptedQueueErr
+	}
+	return data, next, nil
+}
+
+// recoverSegment counts the valid records of the segment and truncates the
+// partial or corrupted records at its end, which are left by a crash.
+func recoverSegment(dir string, seg *segment) (uint64, error) {
+	file, err := os.OpenFile(segmentPath(dir, seg.start), os.O_RDWR, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	var count uint64
+	var offset int64
+	for {
+		_, next, err := readRecord(file, offset, seg.size, true)
+		if err == io.EOF {
+			return count, nil
+		}
+		if err == CorruptedQueueErr {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		offset = next
+		count++
+	}
+
+	if err := file.Truncate(offset); err != nil {
+		return 0, err
+	}
+	seg.size = offset
+	return count, file.Sync()
+}
+
+// checkSegment verifies the records of a read only segment from offset, and
+// returns the number of them. Unlike the last segment, it is not written
+// when a crash happens, so any invalid record is CorruptedQueueErr.
+func checkSegment(dir string, seg *segment, offset int64) (uint64, error) {
+	path := segmentPath(dir, seg.start)
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	var count uint64
+	for {
+		_, next, err := readRecord(file, offset, seg.size, true)
+		if err == io.EOF {
+			return count, nil
+		}
+		if err == CorruptedQueueErr {
+			return 0, fmt.Errorf("%w: %s at %d", CorruptedQueueErr, path, offset)
+		}
+		if err != nil {
+			return 0, err
+		}
+		offset = next
+		count++
+	}
+}
+
+// skipRecords skips up to n records from the start of the segment, and
+// returns the number of skipped records and the offset after them.
+func skipRecords(dir string, seg *segment, n uint64) (uint64, int64, error) {
+	file, err := os.Open(segmentPath(dir, seg.start))
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	var skipped uint64
+	var offset int64
+	for skipped < n {
+		_, next, err := readRecord(file, offset, seg.size, false)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		offset = next
+		skipped++
+	}
+	return skipped, offset, nil
+}
+
+// writeMeta replaces the meta file with the head sequence number and position.
+func writeMeta(dir string, seq uint64, head diskPosition, sync bool) error {
+	var data [metaSize]byte
+	binary.LittleEndian.PutUint64(data[:8], seq)
+	binary.LittleEndian.PutUint64(data[8:16], head.segment)
+	binary.LittleEndian.PutUint64(data[16:24], uint64(head.offset))
+	binary.LittleEndian.PutUint32(data[24:], crc32.ChecksumIEEE(data[:24]))
+
+	path := filepath.Join(dir, metaFile)
+	if err := writeFile(path+tmpExt, data[:], sync); err != nil {
+		return err
+	}
+	if err := os.Rename(path+tmpExt, path); err != nil {
+		return err
+	}
+	if sync {
+		return syncDir(dir)
+	}
+	return nil
+}
+
+// readMeta reads the meta file, found is false if it does not exist.
+func readMeta(dir string) (seq uint64, head diskPosition, found bool, err error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, metaFile))
+	if os.IsNotExist(err) {
+		return 0, head, false, nil
+	}
+	if err != nil {
+		return 0, head, false, err
+	}
+	if len(data) != metaSize || crc32.ChecksumIEEE(data[:24]) != binary.LittleEndian.Uint32(data[24:]) {
+		return 0, head, false, fmt.Errorf("%w: %s", CorruptedQueueErr, metaFile)
+	}
+
+	seq = binary.LittleEndian.Uint64(data[:8])
+	head.segment = binary.LittleEndian.Uint64(data[8:16])
+	head.offset = int64(binary.LittleEndian.Uint64(data[16:24]))
+	return seq, head, true, nil
+}
+
+func writeFile(path string, data []byte, sync bool) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if sync {
+		if err := file.Sync(); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	return file.Close()
+}
+
+// syncDir makes the creating, renaming and removing of files in dir durable.
+func syncDir(dir string) error {
+	file, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return file.Sync()
+}
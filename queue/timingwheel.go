@@ -0,0 +1,199 @@
+package queue
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// InvalidTimingWheelErr is returned when the tick or the wheel size is not positive.
+var InvalidTimingWheelErr = errors.New("tick and wheel size must be positive")
+
+// TimingWheel is a hierarchical timing wheel which schedules large numbers of
+// timers in O(1) time. A wheel has size buckets of tick duration, timers
+// beyond the range of a wheel are put into an overflow wheel whose tick is
+// the range of the lower wheel, and they are moved down to the lower wheels
+// as the time advances. Only the buckets holding timers are scheduled in a
+// DelayQueue, so that the wheel does not wake up for empty ticks.
+// A timer fires within one tick after its expiration, and never before it.
+//
+// every operations over a TimingWheel are synchronized and
+// safe for concurrent usage.
+//
+// Reference:
+//   - Hashed and Hierarchical Timing Wheels, George Varghese and Tony Lauck
+//   - The timing wheel of Apache Kafka
+type TimingWheel struct {
+	mu      sync.Mutex
+	clock   Clock
+	wheel   *wheel
+	buckets *DelayQueue
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// wheel is a level of the timing wheel, times are in nanoseconds.
+type wheel struct {
+	tick     int64
+	size     int64
+	interval int64
+	// current is the start of the current bucket, a multiple of tick
+	current  int64
+	buckets  []*wheelBucket
+	overflow *wheel
+}
+
+type wheelBucket struct {
+	// expiration is the time when the bucket is due, or -1 if not scheduled
+	expiration int64
+	timers     *list.List
+}
+
+// WheelTimer is a timer of TimingWheel.
+type WheelTimer struct {
+	// expiration is rounded up to the tick of the lowest wheel, so that the
+	// timer never fires early
+	expiration int64
+	f          func()
+	wheel      *TimingWheel
+	bucket     *wheelBucket
+	element    *list.Element
+}
+
+func newWheel(tick, size, current int64) *wheel {
+	w := &wheel{
+		tick:     tick,
+		size:     size,
+		interval: tick * size,
+		current:  current - current%tick,
+		buckets:  make([]*wheelBucket, size),
+	}
+	for i := range w.buckets {
+		w.buckets[i] = &wheelBucket{expiration: -1, timers: list.New()}
+	}
+	return w
+}
+
+// add puts the timer into a bucket, it returns false if the timer expired.
+func (w *wheel) add(t *WheelTimer, queue *DelayQueue) bool {
+	if t.expiration < w.current+w.tick {
+		return false
+	}
+
+	if t.expiration < w.current+w.interval {
+		virtual := t.expiration / w.tick
+		bucket := w.buckets[virtual%w.size]
+		t.bucket = bucket
+		t.element = bucket.timers.PushBack(t)
+		if expiration := virtual * w.tick; bucket.expiration != expiration {
+			bucket.expiration = expiration
+			queue.Enqueue(bucket, time.Unix(0, expiration))
+		}
+		return true
+	}
+
+	if w.overflow == nil {
+		w.overflow = newWheel(w.interval, w.size, w.current)
+	}
+	return w.overflow.add(t, queue)
+}
+
+func (w *wheel) advance(now int64) {
+	if now >= w.current+w.tick {
+		w.current = now - now%w.tick
+		if w.overflow != nil {
+			w.overflow.advance(w.current)
+		}
+	}
+}
+
+// NewTimingWheel creates a TimingWheel driven by clock and starts it,
+// SystemClock is used if clock is nil.
+func NewTimingWheel(tick time.Duration, size int, clock Clock) (*TimingWheel, error) {
+	if tick <= 0 || size <= 0 {
+		return nil, InvalidTimingWheelErr
+	}
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tw := &TimingWheel{
+		clock:   clock,
+		wheel:   newWheel(int64(tick), int64(size), clock.Now().UnixNano()),
+		buckets: NewDelayQueue(clock),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go tw.run(ctx)
+	return tw, nil
+}
+
+func (tw *TimingWheel) run(ctx context.Context) {
+	defer close(tw.done)
+
+	for {
+		item, err := tw.buckets.Dequeue(ctx)
+		if err != nil {
+			return
+		}
+		bucket := item.(*wheelBucket)
+
+		tw.mu.Lock()
+		tw.wheel.advance(bucket.expiration)
+		expired := []*WheelTimer{}
+		timers := bucket.timers
+		bucket.timers = list.New()
+		bucket.expiration = -1
+		for e := timers.Front(); e != nil; e = e.Next() {
+			t := e.Value.(*WheelTimer)
+			t.bucket, t.element = nil, nil
+			if !tw.wheel.add(t, tw.buckets) {
+				expired = append(expired, t)
+			}
+		}
+		tw.mu.Unlock()
+
+		for _, t := range expired {
+			go t.f()
+		}
+	}
+}
+
+// AfterFunc waits for the duration to elapse and then calls f in its own goroutine.
+func (tw *TimingWheel) AfterFunc(d time.Duration, f func()) *WheelTimer {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	tick := tw.wheel.tick
+	expiration := tw.clock.Now().Add(d).UnixNano()
+	expiration = (expiration + tick - 1) / tick * tick
+	t := &WheelTimer{expiration: expiration, f: f, wheel: tw}
+	if !tw.wheel.add(t, tw.buckets) {
+		go f()
+	}
+	return t
+}
+
+// Stop stops the wheel, the pending timers never fire.
+func (tw *TimingWheel) Stop() {
+	tw.cancel()
+	<-tw.done
+}
+
+// Stop prevents the timer from firing, it returns false if the timer has
+// already expired or been stopped.
+func (t *WheelTimer) Stop() bool {
+	t.wheel.mu.Lock()
+	defer t.wheel.mu.Unlock()
+
+	if t.bucket == nil {
+		return false
+	}
+	t.bucket.timers.Remove(t.element)
+	t.bucket, t.element = nil, nil
+	return true
+}
@@ -0,0 +1,117 @@
+package queue
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+type firedTimer struct {
+	id  int
+	now time.Time
+}
+
+func TestTimingWheel(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := NewManualClock(start)
+	tick := time.Millisecond
+	wheel, err := NewTimingWheel(tick, 8, clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wheel.Stop()
+
+	// delays span several levels of the wheel, 8ms, 64ms and 512ms
+	r := rand.New(rand.NewSource(1))
+	count := 300
+	delays := make([]time.Duration, count)
+	fired := make(chan firedTimer, count)
+	timers := make([]*WheelTimer, count)
+	for i := range delays {
+		delays[i] = time.Duration(r.Int63n(int64(600 * time.Millisecond)))
+		id := i
+		timers[i] = wheel.AfterFunc(delays[i], func() {
+			fired <- firedTimer{id: id, now: clock.Now()}
+		})
+	}
+
+	// stop some timers
+	stopped := make(map[int]bool)
+	for i := 0; i < count; i += 10 {
+		if !timers[i].Stop() {
+			t.Errorf("Stop pending timer %d return false", i)
+		}
+		stopped[i] = true
+	}
+	if timers[0].Stop() {
+		t.Error("Stop a stopped timer return true")
+	}
+
+	received := 0
+	for now := time.Duration(0); now <= 600*time.Millisecond; now += tick {
+		if now > 0 {
+			clock.Advance(tick)
+		}
+
+		expected := 0
+		for i, delay := range delays {
+			// a timer fires at the first tick not before its expiration
+			if !stopped[i] && delay <= now && delay > now-tick {
+				expected++
+			}
+		}
+		for ; expected > 0; expected-- {
+			f := <-fired
+			elapsed := f.now.Sub(start)
+			if elapsed < delays[f.id] || elapsed >= delays[f.id]+tick {
+				t.Fatalf("Timer %d of %v fires at %v", f.id, delays[f.id], elapsed)
+			}
+			received++
+		}
+	}
+
+	if received != count-len(stopped) {
+		t.Errorf("Got %v expected %v for fired timers", received, count-len(stopped))
+	}
+	if timers[1].Stop() {
+		t.Error("Stop a fired timer return true")
+	}
+}
+
+func TestTimingWheelImmediate(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	wheel, _ := NewTimingWheel(time.Millisecond, 4, clock)
+	defer wheel.Stop()
+
+	fired := make(chan bool)
+	wheel.AfterFunc(0, func() { fired <- true })
+	wheel.AfterFunc(-time.Second, func() { fired <- true })
+	<-fired
+	<-fired
+
+	if _, err := NewTimingWheel(0, 4, clock); err != InvalidTimingWheelErr {
+		t.Errorf("Got %v expected %v for zero tick", err, InvalidTimingWheelErr)
+	}
+}
+
+func TestTimingWheelSystemClock(t *testing.T) {
+	wheel, _ := NewTimingWheel(time.Millisecond, 16, nil)
+	defer wheel.Stop()
+
+	start := time.Now()
+	fired := make(chan time.Time)
+	wheel.AfterFunc(20*time.Millisecond, func() { fired <- time.Now() })
+	if elapsed := (<-fired).Sub(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Timer fires early after %v", elapsed)
+	}
+}
+
+func BenchmarkTimingWheelAfterFunc(b *testing.B) {
+	wheel, _ := NewTimingWheel(time.Millisecond, 64, nil)
+	defer wheel.Stop()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		wheel.AfterFunc(time.Duration(i%1000)*time.Millisecond+time.Hour, func() {}).Stop()
+	}
+}
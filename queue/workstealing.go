@@ -0,0 +1,145 @@
+package queue
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// WorkStealingDeque is the work-stealing deque of Chase and Lev. The owner
+// pushes and pops items at the bottom without locks, and other goroutines
+// steal items from the top concurrently, only the last item is contended by
+// a CAS. The items are stored in a circular array which grows when full.
+//
+// PushBottom and PopBottom must be called by the owner goroutine only, Steal,
+// Size and IsEmpty are safe for concurrent usage.
+//
+// Reference:
+//   - Dynamic Circular Work-Stealing Deque, David Chase and Yossi Lev
+//   - Correct and Efficient Work-Stealing for Weak Memory Models, Nhat Minh Lê et al.
+type WorkStealingDeque struct {
+	// top and bottom are the first fields to be 64-bit aligned for atomic operations
+	top    int64
+	bottom int64
+	array  unsafe.Pointer
+}
+
+// workStealingArray is a circular array, the slots hold *interface{} so that
+// they are read and written atomically.
+type workStealingArray struct {
+	slots []unsafe.Pointer
+}
+
+func (a *workStealingArray) size() int64 {
+	return int64(len(a.slots))
+}
+
+// get returns the raw slot, it is dereferenced only after the item is
+// taken, since a thief may read a slot being overwritten by the owner.
+func (a *workStealingArray) get(i int64) unsafe.Pointer {
+	return atomic.LoadPointer(&a.slots[i&(a.size()-1)])
+}
+
+func (a *workStealingArray) set(i int64, p unsafe.Pointer) {
+	atomic.StorePointer(&a.slots[i&(a.size()-1)], p)
+}
+
+func (a *workStealingArray) put(i int64, item interface{}) {
+	a.set(i, unsafe.Pointer(&item))
+}
+
+// grow returns a new array of double size with the items in [top, bottom).
+func (a *workStealingArray) grow(top, bottom int64) *workStealingArray {
+	grown := &workStealingArray{slots: make([]unsafe.Pointer, 2*len(a.slots))}
+	for i := top; i < bottom; i++ {
+		grown.set(i, a.get(i))
+	}
+	return grown
+}
+
+const workStealingInitialSize = 32
+
+// NewWorkStealingDeque creates a WorkStealingDeque.
+func NewWorkStealingDeque() *WorkStealingDeque {
+	array := &workStealingArray{slots: make([]unsafe.Pointer, workStealingInitialSize)}
+	return &WorkStealingDeque{array: unsafe.Pointer(array)}
+}
+
+func (d *WorkStealingDeque) loadArray() *workStealingArray {
+	return (*workStealingArray)(atomic.LoadPointer(&d.array))
+}
+
+// PushBottom inserts item at the bottom, it must be called by the owner only
+func (d *WorkStealingDeque) PushBottom(item interface{}) {
+	bottom := atomic.LoadInt64(&d.bottom)
+	top := atomic.LoadInt64(&d.top)
+	array := d.loadArray()
+	if bottom-top >= array.size() {
+		array = array.grow(top, bottom)
+		atomic.StorePointer(&d.array, unsafe.Pointer(array))
+	}
+
+	array.put(bottom, item)
+	atomic.StoreInt64(&d.bottom, bottom+1)
+}
+
+// PopBottom removes the bottom item, it must be called by the owner only.
+// It returns false if the deque is empty or the last item is stolen.
+func (d *WorkStealingDeque) PopBottom() (interface{}, bool) {
+	bottom := atomic.LoadInt64(&d.bottom) - 1
+	array := d.loadArray()
+	// reserve the bottom item before reading top, thieves see the new bottom
+	atomic.StoreInt64(&d.bottom, bottom)
+	top := atomic.LoadInt64(&d.top)
+
+	if top > bottom {
+		// empty
+		atomic.StoreInt64(&d.bottom, bottom+1)
+		return nil, false
+	}
+
+	slot := array.get(bottom)
+	if top == bottom {
+		// the last item, race against thieves by advancing top
+		ok := atomic.CompareAndSwapInt64(&d.top, top, top+1)
+		atomic.StoreInt64(&d.bottom, bottom+1)
+		if !ok {
+			return nil, false
+		}
+	}
+	return *(*interface{})(slot), true
+}
+
+// Steal removes the top item, it is safe for concurrent usage. It returns
+// false if the deque is empty or the top item is taken by another goroutine,
+// in which case the caller may retry.
+func (d *WorkStealingDeque) Steal() (interface{}, bool) {
+	top := atomic.LoadInt64(&d.top)
+	bottom := atomic.LoadInt64(&d.bottom)
+	if top >= bottom {
+		return nil, false
+	}
+
+	array := d.loadArray()
+	slot := array.get(top)
+	// a nil slot is read from a stale view of the deque, fail the steal
+	if slot == nil || !atomic.CompareAndSwapInt64(&d.top, top, top+1) {
+		return nil, false
+	}
+	return *(*interface{})(slot), true
+}
+
+// Size returns the number of items, it is a snapshot which may be stale
+// under concurrent usage
+func (d *WorkStealingDeque) Size() int {
+	bottom := atomic.LoadInt64(&d.bottom)
+	top := atomic.LoadInt64(&d.top)
+	if bottom > top {
+		return int(bottom - top)
+	}
+	return 0
+}
+
+// IsEmpty checks if the deque is empty
+func (d *WorkStealingDeque) IsEmpty() bool {
+	return d.Size() == 0
+}
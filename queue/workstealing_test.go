@@ -0,0 +1,202 @@
+package queue
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWorkStealingDeque(t *testing.T) {
+	deque := NewWorkStealingDeque()
+	if _, ok := deque.PopBottom(); ok {
+		t.Error("PopBottom from empty deque")
+	}
+	if _, ok := deque.Steal(); ok {
+		t.Error("Steal from empty deque")
+	}
+
+	// grow beyond the initial size
+	for i := 0; i < 100; i++ {
+		deque.PushBottom(i)
+	}
+	if deque.Size() != 100 {
+		t.Errorf("Got %v expected %v for size", deque.Size(), 100)
+	}
+
+	if item, ok := deque.Steal(); !ok || item != 0 {
+		t.Errorf("Got %v, %v expected %v, %v for steal", item, ok, 0, true)
+	}
+	if item, ok := deque.PopBottom(); !ok || item != 99 {
+		t.Errorf("Got %v, %v expected %v, %v for pop", item, ok, 99, true)
+	}
+	for i := 98; i >= 1; i-- {
+		if item, ok := deque.PopBottom(); !ok || item != i {
+			t.Fatalf("Got %v, %v expected %v, %v for pop", item, ok, i, true)
+		}
+	}
+	if !deque.IsEmpty() {
+		t.Error("Deque should be empty")
+	}
+
+	// reuse the slots after wrapping around
+	for round := 0; round < 10; round++ {
+		for i := 0; i < 20; i++ {
+			deque.PushBottom(i)
+		}
+		for i := 0; i < 20; i++ {
+			if item, ok := deque.Steal(); !ok || item != i {
+				t.Fatalf("Got %v, %v expected %v, %v for steal", item, ok, i, true)
+			}
+		}
+	}
+}
+
+func TestWorkStealingDequeConcurrent(t *testing.T) {
+	deque := NewWorkStealingDeque()
+	count, thieves := 20000, 4
+	taken := make([]int32, count)
+
+	var wg sync.WaitGroup
+	var done int32
+	for i := 0; i < thieves; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for atomic.LoadInt32(&done) == 0 || !deque.IsEmpty() {
+				if item, ok := deque.Steal(); ok {
+					atomic.AddInt32(&taken[item.(int)], 1)
+				} else {
+					runtime.Gosched()
+				}
+			}
+		}()
+	}
+
+	// the owner pushes and pops concurrently with the thieves
+	for i := 0; i < count; i++ {
+		deque.PushBottom(i)
+		if i%3 == 0 {
+			if item, ok := deque.PopBottom(); ok {
+				atomic.AddInt32(&taken[item.(int)], 1)
+			}
+		}
+	}
+	for {
+		item, ok := deque.PopBottom()
+		if !ok {
+			if deque.IsEmpty() {
+				break
+			}
+			continue
+		}
+		atomic.AddInt32(&taken[item.(int)], 1)
+	}
+	atomic.StoreInt32(&done, 1)
+	wg.Wait()
+
+	for i, n := range taken {
+		if n != 1 {
+			t.Fatalf("Item %d is taken %d times", i, n)
+		}
+	}
+}
+
+// fib spawns subtasks recursively and adds the leaves to sum.
+func fib(n int, sum *int64) Task {
+	return func(w *Worker) {
+		if n < 2 {
+			atomic.AddInt64(sum, int64(n))
+			return
+		}
+		w.Spawn(fib(n-1, sum))
+		w.Spawn(fib(n-2, sum))
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	s := NewScheduler(4)
+
+	var sum int64
+	if err := s.Submit(fib(20, &sum)); err != nil {
+		t.Fatal(err)
+	}
+	s.Wait()
+	if sum != 6765 {
+		t.Errorf("Got %v expected %v for fib(20)", sum, 6765)
+	}
+
+	var count int64
+	for i := 0; i < 1000; i++ {
+		s.Submit(func(w *Worker) {
+			atomic.AddInt64(&count, 1)
+		})
+	}
+	s.Close()
+	if count != 1000 {
+		t.Errorf("Got %v expected %v for finished tasks", count, 1000)
+	}
+	if err := s.Submit(func(w *Worker) {}); err != SchedulerClosedErr {
+		t.Errorf("Got %v expected %v for submit after close", err, SchedulerClosedErr)
+	}
+	s.Close()
+}
+
+func TestSchedulerSubmitDuringClose(t *testing.T) {
+	s := NewScheduler(4)
+
+	var submitted, finished int64
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				err := s.Submit(func(w *Worker) {
+					atomic.AddInt64(&finished, 1)
+				})
+				if err == SchedulerClosedErr {
+					return
+				}
+				atomic.AddInt64(&submitted, 1)
+			}
+		}()
+	}
+	s.Close()
+	wg.Wait()
+	if finished != submitted {
+		t.Errorf("Got %v expected %v for finished tasks", finished, submitted)
+	}
+}
+
+func TestSchedulerPark(t *testing.T) {
+	s := NewScheduler(4)
+
+	// every worker parks without work
+	for atomic.LoadInt32(&s.sleeping) != 4 {
+		runtime.Gosched()
+	}
+
+	done := make(chan int)
+	s.Submit(func(w *Worker) {
+		w.Spawn(func(w *Worker) {
+			done <- w.ID()
+		})
+	})
+	<-done
+	s.Close()
+	if s.sleeping != 0 {
+		t.Errorf("Got %v expected %v for parked workers after close", s.sleeping, 0)
+	}
+}
+
+func BenchmarkSchedulerFib(b *testing.B) {
+	s := NewScheduler(0)
+	defer s.Close()
+
+	for i := 0; i < b.N; i++ {
+		var sum int64
+		s.Submit(fib(18, &sum))
+		s.Wait()
+	}
+}
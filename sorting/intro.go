@@ -0,0 +1,120 @@
+package sorting
+
+import (
+	"github.com/aiden0z/kit/base"
+)
+
+// IntroSort sorts data by introsort, a quicksort with median of three pivots
+// that switches to heapsort when the recursion is too deep, so the worst case
+// is O(n*log(n)). The sort is not stable.
+func IntroSort(data []base.Comparable) {
+	introSort(data, 0, len(data), maxDepth(len(data)))
+}
+
+// maxDepth returns 2*ceil(log2(n+1)), the recursion depth limit of introsort.
+func maxDepth(n int) int {
+	depth := 0
+	for i := n; i > 0; i >>= 1 {
+		depth++
+	}
+	return depth * 2
+}
+
+func introSort(data []base.Comparable, lo, hi, depth int) {
+	for hi-lo > insertionThreshold {
+		if depth == 0 {
+			heapSort(data, lo, hi)
+			return
+		}
+		depth--
+
+		p := partition(data, lo, hi)
+		// recurse into the smaller part to bound the stack
+		if p-lo < hi-p {
+			introSort(data, lo, p, depth)
+			lo = p + 1
+		} else {
+			introSort(data, p+1, hi, depth)
+			hi = p
+		}
+	}
+	insertionSort(data, lo, hi)
+}
+
+// medianOfThree moves the median of data[a], data[b], data[c] to data[a].
+func medianOfThree(data []base.Comparable, a, b, c int) {
+	if less(data[b], data[a]) {
+		data[a], data[b] = data[b], data[a]
+	}
+	if less(data[c], data[b]) {
+		data[b], data[c] = data[c], data[b]
+		if less(data[b], data[a]) {
+			data[a], data[b] = data[b], data[a]
+		}
+	}
+	// now data[a] <= data[b] <= data[c]
+	data[a], data[b] = data[b], data[a]
+}
+
+// partition partitions data[lo:hi] (hi-lo >= 3) around a median of three
+// pivot, and returns the final index of the pivot, elements before it are not
+// greater and elements after it are not less.
+func partition(data []base.Comparable, lo, hi int) int {
+	mid := int(uint(lo+hi) >> 1)
+	medianOfThree(data, lo, mid, hi-1)
+	pivot := data[lo]
+
+	// Hoare partition, stops on equal elements to split runs of duplicates evenly
+	i, j := lo+1, hi-1
+	for {
+		for i <= j && less(data[i], pivot) {
+			i++
+		}
+		for i <= j && less(pivot, data[j]) {
+			j--
+		}
+		if i >= j {
+			break
+		}
+		data[i], data[j] = data[j], data[i]
+		i++
+		j--
+	}
+	data[lo], data[j] = data[j], data[lo]
+	return j
+}
+
+// HeapSort sorts data by heapsort, it takes O(n*log(n)) time and no extra
+// space. The sort is not stable.
+func HeapSort(data []base.Comparable) {
+	heapSort(data, 0, len(data))
+}
+
+func heapSort(data []base.Comparable, lo, hi int) {
+	n := hi - lo
+	for i := n/2 - 1; i >= 0; i-- {
+		siftDown(data, lo, i, n)
+	}
+	for i := n - 1; i > 0; i-- {
+		data[lo], data[lo+i] = data[lo+i], data[lo]
+		siftDown(data, lo, 0, i)
+	}
+}
+
+// siftDown restores the max heap data[lo:lo+n] from the root lo+i.
+func siftDown(data []base.Comparable, lo, i, n int) {
+	for {
+		child := 2*i + 1
+		if child >= n {
+			return
+		}
+		if child+1 < n && less(data[lo+child], data[lo+child+1]) {
+			child++
+		}
+		if !less(data[lo+i], data[lo+child]) {
+			return
+		}
+		data[lo+i], data[lo+child] = data[lo+child], data[lo+i]
+		i = child
+	}
+}
@@ -0,0 +1,53 @@
+package sorting
+
+import (
+	"github.com/aiden0z/kit/base"
+)
+
+// MergeSort sorts data stably by top-down merge sort, it takes O(n*log(n))
+// time and O(n) extra space.
+func MergeSort(data []base.Comparable) {
+	buffer := make([]base.Comparable, len(data))
+	mergeSort(data, buffer, 0, len(data))
+}
+
+func mergeSort(data, buffer []base.Comparable, lo, hi int) {
+	if hi-lo <= insertionThreshold {
+		insertionSort(data, lo, hi)
+		return
+	}
+
+	mid := int(uint(lo+hi) >> 1)
+	mergeSort(data, buffer, lo, mid)
+	mergeSort(data, buffer, mid, hi)
+	// already in order
+	if !less(data[mid], data[mid-1]) {
+		return
+	}
+	merge(data, buffer, lo, mid, hi)
+}
+
+// merge merges the sorted data[lo:mid] and data[mid:hi] stably, the left half
+// is copied to buffer.
+func merge(data, buffer []base.Comparable, lo, mid, hi int) {
+	left := buffer[:mid-lo]
+	copy(left, data[lo:mid])
+
+	i, j, k := 0, mid, lo
+	for i < len(left) && j < hi {
+		// take from the right only if strictly less to keep stable
+		if less(data[j], left[i]) {
+			data[k] = data[j]
+			j++
+		} else {
+			data[k] = left[i]
+			i++
+		}
+		k++
+	}
+	copy(data[k:], left[i:])
+
+	for n := range left {
+		left[n] = nil
+	}
+}
@@ -0,0 +1,49 @@
+package sorting
+
+import (
+	"github.com/aiden0z/kit/base"
+)
+
+// LowerBound returns the index of the first element of the sorted data not
+// less than target, or len(data) if there is none.
+func LowerBound(data []base.Comparable, target base.Comparable) int {
+	lo, hi := 0, len(data)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if less(data[mid], target) {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// UpperBound returns the index of the first element of the sorted data
+// greater than target, or len(data) if there is none.
+func UpperBound(data []base.Comparable, target base.Comparable) int {
+	lo, hi := 0, len(data)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if less(target, data[mid]) {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo
+}
+
+// BinarySearch returns the index of the first element of the sorted data
+// equal to target and true, or the index where target would be inserted and
+// false.
+func BinarySearch(data []base.Comparable, target base.Comparable) (int, bool) {
+	i := LowerBound(data, target)
+	return i, i < len(data) && data[i].CompareTo(target) == 0
+}
+
+// EqualRange returns the range [lo, hi) of the elements of the sorted data
+// equal to target.
+func EqualRange(data []base.Comparable, target base.Comparable) (lo, hi int) {
+	return LowerBound(data, target), UpperBound(data, target)
+}
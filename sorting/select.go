@@ -0,0 +1,83 @@
+package sorting
+
+import (
+	"github.com/aiden0z/kit/base"
+)
+
+// NthElement rearranges data so that data[n] is the element which would be
+// there if data were sorted, elements before it are not greater and elements
+// after it are not less, and returns data[n]. It is a quickselect which falls
+// back to heapsort when the recursion is too deep, it takes O(len(data))
+// time on average. n must be in [0, len(data)).
+func NthElement(data []base.Comparable, n int) base.Comparable {
+	lo, hi := 0, len(data)
+	depth := maxDepth(len(data))
+	for hi-lo > insertionThreshold {
+		if depth == 0 {
+			heapSort(data, lo, hi)
+			return data[n]
+		}
+		depth--
+
+		p := partition(data, lo, hi)
+		if n == p {
+			return data[n]
+		} else if n < p {
+			hi = p
+		} else {
+			lo = p + 1
+		}
+	}
+	insertionSort(data, lo, hi)
+	return data[n]
+}
+
+// PartialSort rearranges data so that data[:k] are the k smallest elements in
+// ascending order, the order of the rest is unspecified. It takes
+// O(len(data)*log(k)) time. k is limited to [0, len(data)].
+func PartialSort(data []base.Comparable, k int) {
+	if k > len(data) {
+		k = len(data)
+	}
+	if k <= 0 {
+		return
+	}
+
+	// keep the k smallest elements in a max heap
+	for i := k/2 - 1; i >= 0; i-- {
+		siftDown(data, 0, i, k)
+	}
+	for i := k; i < len(data); i++ {
+		if less(data[i], data[0]) {
+			data[0], data[i] = data[i], data[0]
+			siftDown(data, 0, 0, k)
+		}
+	}
+	for i := k - 1; i > 0; i-- {
+		data[0], data[i] = data[i], data[0]
+		siftDown(data, 0, 0, i)
+	}
+}
+
+// TopK returns the k largest elements of data in descending order, data is
+// not modified. It takes O(len(data) + k*log(k)) time on average. k is
+// limited to [0, len(data)].
+func TopK(data []base.Comparable, k int) []base.Comparable {
+	if k > len(data) {
+		k = len(data)
+	}
+	if k <= 0 {
+		return []base.Comparable{}
+	}
+
+	values := make([]base.Comparable, len(data))
+	copy(values, data)
+	if k < len(values) {
+		NthElement(values, len(values)-k)
+	}
+
+	top := values[len(values)-k:]
+	IntroSort(top)
+	reverse(top, 0, k)
+	return top
+}
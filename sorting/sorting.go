@@ -0,0 +1,45 @@
+// Package sorting implements sorting and searching algorithms over []base.Comparable,
+// such as the slices made by base.NewIntComparableSlice. Elements are ordered
+// by CompareTo, all functions sort in place in ascending order.
+//
+// Reference:
+// - Introspective Sorting and Selection Algorithms, David R. Musser
+// - Tim Peters, listsort.txt in CPython
+package sorting
+
+import (
+	"github.com/aiden0z/kit/base"
+)
+
+// insertionThreshold is the size of ranges sorted by insertion sort.
+const insertionThreshold = 12
+
+func less(a, b base.Comparable) bool {
+	return a.CompareTo(b) < 0
+}
+
+// IsSorted checks if data is sorted in ascending order.
+func IsSorted(data []base.Comparable) bool {
+	for i := len(data) - 1; i > 0; i-- {
+		if less(data[i], data[i-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// insertionSort sorts data[lo:hi] stably.
+func insertionSort(data []base.Comparable, lo, hi int) {
+	for i := lo + 1; i < hi; i++ {
+		for j := i; j > lo && less(data[j], data[j-1]); j-- {
+			data[j], data[j-1] = data[j-1], data[j]
+		}
+	}
+}
+
+// reverse reverses data[lo:hi].
+func reverse(data []base.Comparable, lo, hi int) {
+	for i, j := lo, hi-1; i < j; i, j = i+1, j-1 {
+		data[i], data[j] = data[j], data[i]
+	}
+}
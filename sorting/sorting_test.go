@@ -0,0 +1,283 @@
+package sorting
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/aiden0z/kit/base"
+)
+
+// pair is ordered by key only, index records the original position to check stability.
+type pair struct {
+	key, index int
+}
+
+func (p pair) CompareTo(o base.Comparable) int {
+	return p.key - o.(pair).key
+}
+
+func (p pair) String() string {
+	return base.Int(p.key).String()
+}
+
+func randomInts(n, max int, seed int64) []base.Comparable {
+	r := rand.New(rand.NewSource(seed))
+	values := make([]int, n)
+	for i := range values {
+		values[i] = r.Intn(max)
+	}
+	return base.NewIntComparableSlice(values)
+}
+
+func inputs() map[string][]base.Comparable {
+	sorted := make([]int, 1000)
+	reversed := make([]int, 1000)
+	sawtooth := make([]int, 1000)
+	for i := range sorted {
+		sorted[i] = i
+		reversed[i] = 1000 - i
+		sawtooth[i] = i % 37
+	}
+
+	return map[string][]base.Comparable{
+		"empty":      {},
+		"one":        {base.Int(1)},
+		"random":     randomInts(1000, 1000000, 1),
+		"duplicates": randomInts(1000, 5, 2),
+		"small":      randomInts(10, 100, 3),
+		"sorted":     base.NewIntComparableSlice(sorted),
+		"reversed":   base.NewIntComparableSlice(reversed),
+		"sawtooth":   base.NewIntComparableSlice(sawtooth),
+		"equal":      base.NewIntComparableSlice(make([]int, 100)),
+	}
+}
+
+func copyOf(data []base.Comparable) []base.Comparable {
+	values := make([]base.Comparable, len(data))
+	copy(values, data)
+	return values
+}
+
+func TestSorts(t *testing.T) {
+	sorts := map[string]func([]base.Comparable){
+		"MergeSort": MergeSort,
+		"IntroSort": IntroSort,
+		"HeapSort":  HeapSort,
+		"TimSort":   TimSort,
+		// falls back to heapsort immediately
+		"IntroSort with depth 1": func(data []base.Comparable) { introSort(data, 0, len(data), 1) },
+	}
+
+	for sortName, sortFunc := range sorts {
+		for inputName, input := range inputs() {
+			data := copyOf(input)
+			sortFunc(data)
+			if !IsSorted(data) || len(data) != len(input) {
+				t.Errorf("%s of %s input is not sorted", sortName, inputName)
+			}
+
+			expected := copyOf(input)
+			sort.Slice(expected, func(i, j int) bool { return less(expected[i], expected[j]) })
+			for i := range expected {
+				if data[i].CompareTo(expected[i]) != 0 {
+					t.Errorf("Got %v expected %v at %d for %s of %s input", data[i], expected[i], i, sortName, inputName)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestStableSorts(t *testing.T) {
+	sorts := map[string]func([]base.Comparable){
+		"MergeSort": MergeSort,
+		"TimSort":   TimSort,
+	}
+
+	r := rand.New(rand.NewSource(4))
+	for _, n := range []int{10, 100, 5000} {
+		input := make([]base.Comparable, n)
+		for i := range input {
+			input[i] = pair{key: r.Intn(n / 5), index: i}
+		}
+		// descending runs with equal keys
+		for i := 0; i < n/4; i++ {
+			input[i] = pair{key: n - i/3, index: i}
+		}
+
+		for sortName, sortFunc := range sorts {
+			data := copyOf(input)
+			sortFunc(data)
+			for i := 1; i < n; i++ {
+				a, b := data[i-1].(pair), data[i].(pair)
+				if a.key > b.key || (a.key == b.key && a.index > b.index) {
+					t.Errorf("%s is not stable at %d: %v %v", sortName, i, a, b)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestNthElement(t *testing.T) {
+	for inputName, input := range inputs() {
+		expected := copyOf(input)
+		MergeSort(expected)
+
+		for n := 0; n < len(input); n += len(input)/7 + 1 {
+			data := copyOf(input)
+			value := NthElement(data, n)
+			if value.CompareTo(expected[n]) != 0 {
+				t.Errorf("Got %v expected %v for %d-th element of %s input", value, expected[n], n, inputName)
+			}
+			for i := range data {
+				if (i < n && less(value, data[i])) || (i > n && less(data[i], value)) {
+					t.Errorf("Element %v at %d is misplaced for %d-th element of %s input", data[i], i, n, inputName)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestPartialSortAndTopK(t *testing.T) {
+	input := randomInts(500, 100, 5)
+	expected := copyOf(input)
+	MergeSort(expected)
+
+	for _, k := range []int{-1, 0, 1, 10, 499, 500, 600} {
+		data := copyOf(input)
+		PartialSort(data, k)
+		for i := 0; i < k && i < len(data); i++ {
+			if data[i].CompareTo(expected[i]) != 0 {
+				t.Errorf("Got %v expected %v at %d for partial sort of %d", data[i], expected[i], i, k)
+				break
+			}
+		}
+
+		top := TopK(input, k)
+		size := k
+		if size < 0 {
+			size = 0
+		} else if size > len(input) {
+			size = len(input)
+		}
+		if len(top) != size {
+			t.Errorf("Got %v expected %v for size of top %d", len(top), size, k)
+		}
+		for i := range top {
+			if top[i].CompareTo(expected[len(expected)-1-i]) != 0 {
+				t.Errorf("Got %v expected %v at %d for top %d", top[i], expected[len(expected)-1-i], i, k)
+				break
+			}
+		}
+	}
+
+	original := randomInts(500, 100, 5)
+	for i := range input {
+		if input[i] != original[i] {
+			t.Error("TopK modified the input")
+			break
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	data := base.NewIntComparableSlice([]int{1, 3, 3, 3, 5, 8})
+
+	tests := []struct {
+		target int
+		lower  int
+		upper  int
+		found  bool
+	}{
+		{0, 0, 0, false},
+		{1, 0, 1, true},
+		{2, 1, 1, false},
+		{3, 1, 4, true},
+		{8, 5, 6, true},
+		{9, 6, 6, false},
+	}
+
+	for _, test := range tests {
+		target := base.Int(test.target)
+		if lower := LowerBound(data, target); lower != test.lower {
+			t.Errorf("Got %v expected %v for lower bound of %v", lower, test.lower, target)
+		}
+		if upper := UpperBound(data, target); upper != test.upper {
+			t.Errorf("Got %v expected %v for upper bound of %v", upper, test.upper, target)
+		}
+		if i, found := BinarySearch(data, target); i != test.lower || found != test.found {
+			t.Errorf("Got %v, %v expected %v, %v for search of %v", i, found, test.lower, test.found, target)
+		}
+		if lo, hi := EqualRange(data, target); lo != test.lower || hi != test.upper {
+			t.Errorf("Got [%v, %v) expected [%v, %v) for equal range of %v", lo, hi, test.lower, test.upper, target)
+		}
+	}
+
+	if i, found := BinarySearch([]base.Comparable{}, base.Int(1)); i != 0 || found {
+		t.Errorf("Got %v, %v expected %v, %v for search in empty slice", i, found, 0, false)
+	}
+}
+
+func benchmarkSort(b *testing.B, input []base.Comparable, sortFunc func([]base.Comparable)) {
+	data := make([]base.Comparable, len(input))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		copy(data, input)
+		b.StartTimer()
+		sortFunc(data)
+	}
+}
+
+func sortSlice(data []base.Comparable) {
+	sort.Slice(data, func(i, j int) bool { return less(data[i], data[j]) })
+}
+
+func sortSliceStable(data []base.Comparable) {
+	sort.SliceStable(data, func(i, j int) bool { return less(data[i], data[j]) })
+}
+
+func BenchmarkSortRandom(b *testing.B) {
+	input := randomInts(10000, 1000000, 1)
+	b.Run("MergeSort", func(b *testing.B) { benchmarkSort(b, input, MergeSort) })
+	b.Run("IntroSort", func(b *testing.B) { benchmarkSort(b, input, IntroSort) })
+	b.Run("HeapSort", func(b *testing.B) { benchmarkSort(b, input, HeapSort) })
+	b.Run("TimSort", func(b *testing.B) { benchmarkSort(b, input, TimSort) })
+	b.Run("sort.Slice", func(b *testing.B) { benchmarkSort(b, input, sortSlice) })
+	b.Run("sort.SliceStable", func(b *testing.B) { benchmarkSort(b, input, sortSliceStable) })
+}
+
+func BenchmarkSortNearlySorted(b *testing.B) {
+	input := make([]int, 10000)
+	for i := range input {
+		input[i] = i
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		x, y := r.Intn(len(input)), r.Intn(len(input))
+		input[x], input[y] = input[y], input[x]
+	}
+	data := base.NewIntComparableSlice(input)
+
+	b.Run("MergeSort", func(b *testing.B) { benchmarkSort(b, data, MergeSort) })
+	b.Run("IntroSort", func(b *testing.B) { benchmarkSort(b, data, IntroSort) })
+	b.Run("TimSort", func(b *testing.B) { benchmarkSort(b, data, TimSort) })
+	b.Run("sort.Slice", func(b *testing.B) { benchmarkSort(b, data, sortSlice) })
+	b.Run("sort.SliceStable", func(b *testing.B) { benchmarkSort(b, data, sortSliceStable) })
+}
+
+func BenchmarkSelect(b *testing.B) {
+	input := randomInts(10000, 1000000, 1)
+	b.Run("NthElement", func(b *testing.B) {
+		benchmarkSort(b, input, func(data []base.Comparable) { NthElement(data, len(data)/2) })
+	})
+	b.Run("PartialSort", func(b *testing.B) {
+		benchmarkSort(b, input, func(data []base.Comparable) { PartialSort(data, 10) })
+	})
+	b.Run("TopK", func(b *testing.B) {
+		benchmarkSort(b, input, func(data []base.Comparable) { TopK(data, 10) })
+	})
+}
@@ -0,0 +1,138 @@
+package sorting
+
+import (
+	"github.com/aiden0z/kit/base"
+)
+
+// minMerge is the size of arrays sorted by binary insertion sort only.
+const minMerge = 32
+
+type run struct {
+	base, length int
+}
+
+// TimSort sorts data stably by a simplified timsort. It finds the natural
+// runs of data, extends short runs by binary insertion sort, and merges runs
+// with balanced lengths, so sorted, reversed or partially sorted data is
+// sorted in nearly linear time. Galloping mode is not implemented, only the
+// already placed elements around the runs are skipped before merging.
+func TimSort(data []base.Comparable) {
+	n := len(data)
+	if n < 2 {
+		return
+	}
+	if n < minMerge {
+		length := countRunAndMakeAscending(data, 0, n)
+		binaryInsertionSort(data, 0, n, length)
+		return
+	}
+
+	minRun := minRunLength(n)
+	buffer := make([]base.Comparable, n)
+	runs := []run{}
+	for lo := 0; lo < n; {
+		length := countRunAndMakeAscending(data, lo, n)
+		if length < minRun {
+			force := minRun
+			if n-lo < force {
+				force = n - lo
+			}
+			binaryInsertionSort(data, lo, lo+force, lo+length)
+			length = force
+		}
+
+		runs = append(runs, run{base: lo, length: length})
+		runs = mergeCollapse(data, buffer, runs)
+		lo += length
+	}
+
+	for len(runs) > 1 {
+		n := len(runs) - 2
+		if n > 0 && runs[n-1].length < runs[n+1].length {
+			n--
+		}
+		runs = mergeAt(data, buffer, runs, n)
+	}
+}
+
+// minRunLength returns the minimum run length, a number in [minMerge/2, minMerge]
+// so that n/minRun is equal to or slightly less than a power of two.
+func minRunLength(n int) int {
+	r := 0
+	for n >= minMerge {
+		r |= n & 1
+		n >>= 1
+	}
+	return n + r
+}
+
+// countRunAndMakeAscending returns the length of the run beginning at lo,
+// a descending run is strictly descending and is reversed in place, so that
+// the sort is stable.
+func countRunAndMakeAscending(data []base.Comparable, lo, hi int) int {
+	i := lo + 1
+	if i == hi {
+		return 1
+	}
+
+	if less(data[i], data[lo]) {
+		for i++; i < hi && less(data[i], data[i-1]); i++ {
+		}
+		reverse(data, lo, i)
+	} else {
+		for i++; i < hi && !less(data[i], data[i-1]); i++ {
+		}
+	}
+	return i - lo
+}
+
+// binaryInsertionSort sorts data[lo:hi] of which data[lo:start] is sorted.
+func binaryInsertionSort(data []base.Comparable, lo, hi, start int) {
+	if start == lo {
+		start++
+	}
+	for ; start < hi; start++ {
+		pivot := data[start]
+		// insert after the equal elements to keep stable
+		pos := lo + UpperBound(data[lo:start], pivot)
+		copy(data[pos+1:start+1], data[pos:start])
+		data[pos] = pivot
+	}
+}
+
+// mergeCollapse merges runs until the lengths of runs on the stack satisfy
+// runs[i-2] > runs[i-1] + runs[i] and runs[i-1] > runs[i].
+func mergeCollapse(data, buffer []base.Comparable, runs []run) []run {
+	for len(runs) > 1 {
+		n := len(runs) - 2
+		if (n > 0 && runs[n-1].length <= runs[n].length+runs[n+1].length) ||
+			(n > 1 && runs[n-2].length <= runs[n-1].length+runs[n].length) {
+			if runs[n-1].length < runs[n+1].length {
+				n--
+			}
+		} else if runs[n].length > runs[n+1].length {
+			break
+		}
+		runs = mergeAt(data, buffer, runs, n)
+	}
+	return runs
+}
+
+// mergeAt merges runs[i] and runs[i+1].
+func mergeAt(data, buffer []base.Comparable, runs []run, i int) []run {
+	lo, mid := runs[i].base, runs[i+1].base
+	hi := mid + runs[i+1].length
+	runs[i].length += runs[i+1].length
+	runs = append(runs[:i+1], runs[i+2:]...)
+
+	// elements of the left run not greater than the first element of the
+	// right run are in place, so are elements of the right run not less than
+	// the last element of the left run
+	lo += UpperBound(data[lo:mid], data[mid])
+	if lo == mid {
+		return runs
+	}
+	hi = mid + LowerBound(data[mid:hi], data[mid-1])
+	merge(data, buffer, lo, mid, hi)
+	return runs
+}
@@ -0,0 +1,90 @@
+package stack
+
+import (
+	"github.com/aiden0z/kit/base"
+)
+
+// MinMaxStack is a stack of base.Comparable which reports the minimum and the
+// maximum items in O(1), every entry records the extremes of the items below
+// it. It is not safe for concurrent usage.
+type MinMaxStack struct {
+	stack *Stack
+}
+
+type minMaxEntry struct {
+	value    base.Comparable
+	min, max base.Comparable
+}
+
+// NewMinMaxStack return a new MinMaxStack.
+func NewMinMaxStack(size uint) *MinMaxStack {
+	return &MinMaxStack{stack: NewStack(size)}
+}
+
+// Len return the size of items in stack
+func (s *MinMaxStack) Len() int {
+	return s.stack.Len()
+}
+
+// IsEmpty return if the stack is empty
+func (s *MinMaxStack) IsEmpty() bool {
+	return s.stack.IsEmpty()
+}
+
+func (s *MinMaxStack) top() *minMaxEntry {
+	if item := s.stack.Peek(); item != nil {
+		return item.(*minMaxEntry)
+	}
+	return nil
+}
+
+// Push item to stack
+func (s *MinMaxStack) Push(value base.Comparable) {
+	entry := &minMaxEntry{value: value, min: value, max: value}
+	if top := s.top(); top != nil {
+		if top.min.CompareTo(value) < 0 {
+			entry.min = top.min
+		}
+		if top.max.CompareTo(value) > 0 {
+			entry.max = top.max
+		}
+	}
+	s.stack.Push(entry)
+}
+
+// Pop the top item out, if stack is empty, will return nil
+func (s *MinMaxStack) Pop() base.Comparable {
+	if item := s.stack.Pop(); item != nil {
+		return item.(*minMaxEntry).value
+	}
+	return nil
+}
+
+// Peek return and not pop the top item
+func (s *MinMaxStack) Peek() base.Comparable {
+	if top := s.top(); top != nil {
+		return top.value
+	}
+	return nil
+}
+
+// Min returns the minimum item in O(1), if stack is empty, will return nil
+func (s *MinMaxStack) Min() base.Comparable {
+	if top := s.top(); top != nil {
+		return top.min
+	}
+	return nil
+}
+
+// Max returns the maximum item in O(1), if stack is empty, will return nil
+func (s *MinMaxStack) Max() base.Comparable {
+	if top := s.top(); top != nil {
+		return top.max
+	}
+	return nil
+}
+
+// Clear removes all items
+func (s *MinMaxStack) Clear() {
+	s.stack.Clear()
+}
@@ -0,0 +1,55 @@
+package stack
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/aiden0z/kit/base"
+)
+
+func TestMinMaxStack(t *testing.T) {
+	s := NewMinMaxStack(0)
+	if s.Min() != nil || s.Max() != nil || s.Pop() != nil || s.Peek() != nil {
+		t.Error("Empty MinMaxStack not return nil")
+	}
+
+	r := rand.New(rand.NewSource(1))
+	values := []int{}
+	for i := 0; i < 1000; i++ {
+		if len(values) > 0 && r.Intn(3) == 0 {
+			expected := values[len(values)-1]
+			values = values[:len(values)-1]
+			if v := s.Pop(); v != base.Int(expected) {
+				t.Fatalf("Got %v expected %v for pop", v, expected)
+			}
+		} else {
+			v := r.Intn(100)
+			values = append(values, v)
+			s.Push(base.Int(v))
+		}
+
+		if len(values) == 0 {
+			continue
+		}
+		min, max := values[0], values[0]
+		for _, v := range values {
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+		}
+		if s.Min() != base.Int(min) || s.Max() != base.Int(max) {
+			t.Fatalf("Got %v, %v expected %v, %v for min and max", s.Min(), s.Max(), min, max)
+		}
+		if s.Len() != len(values) || s.Peek() != base.Int(values[len(values)-1]) {
+			t.Fatalf("Got %v, %v expected %v, %v for size and top", s.Len(), s.Peek(), len(values), values[len(values)-1])
+		}
+	}
+
+	s.Clear()
+	if !s.IsEmpty() || s.Min() != nil {
+		t.Error("MinMaxStack Clear error")
+	}
+}
@@ -0,0 +1,55 @@
+package stack
+
+// PersistentStack is an immutable stack based on cons list, Push and Pop
+// return new versions in O(1) and share the items with the older versions,
+// so that a stack can be forked cheaply. It is safe for concurrent usage.
+type PersistentStack struct {
+	head interface{}
+	tail *PersistentStack
+	size int
+}
+
+var emptyPersistentStack = &PersistentStack{}
+
+// NewPersistentStack return an empty persistent stack
+func NewPersistentStack() *PersistentStack {
+	return emptyPersistentStack
+}
+
+// Len return the size of items in stack
+func (s *PersistentStack) Len() int {
+	return s.size
+}
+
+// IsEmpty return if the stack is empty
+func (s *PersistentStack) IsEmpty() bool {
+	return s.size == 0
+}
+
+// Push returns a new version with item on the top
+func (s *PersistentStack) Push(value interface{}) *PersistentStack {
+	return &PersistentStack{head: value, tail: s, size: s.size + 1}
+}
+
+// Pop returns the top item and the version without it, if stack is empty,
+// will return nil and the stack itself
+func (s *PersistentStack) Pop() (interface{}, *PersistentStack) {
+	if s.size == 0 {
+		return nil, s
+	}
+	return s.head, s.tail
+}
+
+// Peek return the top item
+func (s *PersistentStack) Peek() interface{} {
+	return s.head
+}
+
+// Values returns the items from the bottom to the top
+func (s *PersistentStack) Values() []interface{} {
+	values := make([]interface{}, s.size)
+	for node, i := s, s.size-1; i >= 0; node, i = node.tail, i-1 {
+		values[i] = node.head
+	}
+	return values
+}
@@ -0,0 +1,33 @@
+package stack
+
+import (
+	"testing"
+)
+
+func TestPersistentStack(t *testing.T) {
+	empty := NewPersistentStack()
+	if item, s := empty.Pop(); item != nil || s != empty || !empty.IsEmpty() || empty.Peek() != nil {
+		t.Error("Pop from empty persistent stack error")
+	}
+
+	s1 := empty.Push(1).Push(2)
+	s2 := s1.Push(3)
+	s3 := s1.Push(4)
+
+	if s1.Len() != 2 || s2.Len() != 3 || s3.Len() != 3 || empty.Len() != 0 {
+		t.Errorf("Got %v, %v, %v expected %v, %v, %v for sizes", s1.Len(), s2.Len(), s3.Len(), 2, 3, 3)
+	}
+	if s2.Peek() != 3 || s3.Peek() != 4 || s1.Peek() != 2 {
+		t.Error("Forked persistent stacks share the top")
+	}
+
+	item, rest := s2.Pop()
+	if item != 3 || rest != s1 {
+		t.Errorf("Got %v expected %v for pop", item, 3)
+	}
+
+	values := s3.Values()
+	if len(values) != 3 || values[0] != 1 || values[1] != 2 || values[2] != 4 {
+		t.Errorf("Got %v expected %v for values", values, []int{1, 2, 4})
+	}
+}
@@ -1,23 +1,47 @@
 package stack
 
-type stack struct {
-	data []interface{}
+import (
+	"errors"
+)
+
+var CapacityFullErr = errors.New("full capacity")
+
+// Stack is a LIFO stack based on slice, it is not safe for concurrent usage,
+// use SyncStack instead.
+type Stack struct {
+	data     []interface{}
+	capacity int
 }
 
 // NewStack return a new stack
 // The stack storage capacity will auto increase because the underground
 // storage is slice.
-func NewStack(size uint) *stack {
-	return &stack{data: make([]interface{}, 0, size)}
+func NewStack(size uint) *Stack {
+	return &Stack{data: make([]interface{}, 0, size), capacity: -1}
+}
+
+// NewCappedStack return a new stack holds at most capacity items, Push
+// discards and TryPush rejects the items pushed to the full stack. A negative capacity means
+// unlimited. The storage grows on demand, it is not allocated up front.
+func NewCappedStack(capacity int) *Stack {
+	if capacity < 0 {
+		return NewStack(0)
+	}
+	return &Stack{capacity: capacity}
 }
 
-//Len return the size of items in stack
-func (s *stack) Len() int {
+// Len return the size of items in stack
+func (s *Stack) Len() int {
 	return len(s.data)
 }
 
+// Capacity returns the capacity of the stack, or -1 if unlimited
+func (s *Stack) Capacity() int {
+	return s.capacity
+}
+
 // IsEmpty return if the stack is empty
-func (s *stack) IsEmpty() bool {
+func (s *Stack) IsEmpty() bool {
 	if s.Len() == 0 {
 		return true
 	} else {
@@ -25,25 +49,56 @@ func (s *stack) IsEmpty() bool {
 	}
 }
 
-// Push item to stack
-func (s *stack) Push(value interface{}) {
+// IsFull checks if the stack is full
+func (s *Stack) IsFull() bool {
+	return s.capacity >= 0 && s.Len() >= s.capacity
+}
+
+// Push item to stack, the item is discarded if the capped stack is full,
+// use TryPush to detect it
+func (s *Stack) Push(value interface{}) {
+	s.TryPush(value)
+}
+
+// TryPush push item to stack, returns CapacityFullErr if the stack is full
+func (s *Stack) TryPush(value interface{}) error {
+	if s.IsFull() {
+		return CapacityFullErr
+	}
 	s.data = append(s.data, value)
+	return nil
 }
 
 // Pop the top item out, if stack is empty, will return nil
-func (s *stack) Pop() interface{} {
+func (s *Stack) Pop() interface{} {
 	if s.Len() > 0 {
-		rect := s.data[s.Len() - 1]
-		s.data = s.data[:s.Len() - 1]
+		rect := s.data[s.Len()-1]
+		s.data[s.Len()-1] = nil
+		s.data = s.data[:s.Len()-1]
 		return rect
 	}
 	return nil
 }
 
 // Peek return and not pop the top item
-func (s *stack) Peek() interface{} {
+func (s *Stack) Peek() interface{} {
 	if s.Len() > 0 {
-		return s.data[s.Len() - 1]
+		return s.data[s.Len()-1]
 	}
 	return nil
 }
+
+// Clear removes all items
+func (s *Stack) Clear() {
+	for i := range s.data {
+		s.data[i] = nil
+	}
+	s.data = s.data[:0]
+}
+
+// Values returns a copy of the items from the bottom to the top
+func (s *Stack) Values() []interface{} {
+	values := make([]interface{}, len(s.data))
+	copy(values, s.data)
+	return values
+}
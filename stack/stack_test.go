@@ -20,3 +20,63 @@ func TestStack(t *testing.T) {
 		t.Error("Value pop from stack not equal to which pushed into")
 	}
 }
+
+func TestCappedStack(t *testing.T) {
+	s := NewCappedStack(2)
+	if err := s.TryPush(1); err != nil {
+		t.Error("Stack TryPush error")
+	}
+	if err := s.TryPush(2); err != nil {
+		t.Error("Stack TryPush error")
+	}
+	if !s.IsFull() {
+		t.Error("Stack should be full")
+	}
+	if err := s.TryPush(3); err != CapacityFullErr {
+		t.Errorf("Got %v expected %v for push to full stack", err, CapacityFullErr)
+	}
+	if s.Capacity() != 2 || s.Len() != 2 {
+		t.Errorf("Got %v, %v expected %v, %v for capacity and size", s.Capacity(), s.Len(), 2, 2)
+	}
+
+	// Push discards the item
+	s.Push(3)
+	if s.Len() != 2 || s.Peek() != 2 {
+		t.Errorf("Got %v, %v expected %v, %v for size and top", s.Len(), s.Peek(), 2, 2)
+	}
+
+	s.Pop()
+	if err := s.TryPush(3); err != nil {
+		t.Error("Stack TryPush error after pop")
+	}
+
+	unlimited := NewCappedStack(-1)
+	for i := 0; i < 100; i++ {
+		if err := unlimited.TryPush(i); err != nil {
+			t.Error("Unlimited stack TryPush error")
+		}
+	}
+}
+
+func TestStackValuesAndClear(t *testing.T) {
+	s := NewStack(0)
+	for i := 0; i < 5; i++ {
+		s.Push(i)
+	}
+
+	values := s.Values()
+	for i, v := range values {
+		if v != i {
+			t.Errorf("Got %v expected %v for values[%d]", v, i, i)
+		}
+	}
+	values[0] = 100
+	if s.Values()[0] != 0 {
+		t.Error("Values is not a copy")
+	}
+
+	s.Clear()
+	if !s.IsEmpty() || s.Peek() != nil {
+		t.Error("Stack Clear error")
+	}
+}
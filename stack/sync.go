@@ -0,0 +1,134 @@
+package stack
+
+import (
+	"context"
+	"sync"
+)
+
+// SyncStack is a Stack which every operations are synchronized and safe for
+// concurrent usage.
+type SyncStack struct {
+	sync.RWMutex
+	stack *Stack
+	// wait is closed when an item is pushed, to wake up the PopWait callers
+	wait chan struct{}
+}
+
+// NewSyncStack creates a SyncStack.
+func NewSyncStack(size uint) *SyncStack {
+	return &SyncStack{stack: NewStack(size)}
+}
+
+// NewCappedSyncStack creates a SyncStack holds at most capacity items.
+func NewCappedSyncStack(capacity int) *SyncStack {
+	return &SyncStack{stack: NewCappedStack(capacity)}
+}
+
+// Len return the size of items in stack
+func (s *SyncStack) Len() int {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.stack.Len()
+}
+
+// Capacity returns the capacity of the stack, or -1 if unlimited
+func (s *SyncStack) Capacity() int {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.stack.Capacity()
+}
+
+// IsEmpty checks if the stack is empty
+func (s *SyncStack) IsEmpty() bool {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.stack.IsEmpty()
+}
+
+// IsFull checks if the stack is full
+func (s *SyncStack) IsFull() bool {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.stack.IsFull()
+}
+
+// Push item to stack, the item is discarded if the capped stack is full,
+// use TryPush to detect it
+func (s *SyncStack) Push(value interface{}) {
+	s.TryPush(value)
+}
+
+// TryPush push item to stack, returns CapacityFullErr if the stack is full
+func (s *SyncStack) TryPush(value interface{}) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.stack.TryPush(value); err != nil {
+		return err
+	}
+	if s.wait != nil {
+		close(s.wait)
+		s.wait = nil
+	}
+	return nil
+}
+
+// Pop the top item out, if stack is empty, will return nil
+func (s *SyncStack) Pop() interface{} {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.stack.Pop()
+}
+
+// PopWait pops the top item out, blocks until an item is pushed if the stack
+// is empty, or returns the error of ctx if ctx is done first.
+func (s *SyncStack) PopWait(ctx context.Context) (interface{}, error) {
+	for {
+		s.Lock()
+		if !s.stack.IsEmpty() {
+			item := s.stack.Pop()
+			s.Unlock()
+			return item, nil
+		}
+		if s.wait == nil {
+			s.wait = make(chan struct{})
+		}
+		wait := s.wait
+		s.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Peek return and not pop the top item
+func (s *SyncStack) Peek() interface{} {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.stack.Peek()
+}
+
+// Clear removes all items
+func (s *SyncStack) Clear() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.stack.Clear()
+}
+
+// Values returns a copy of the items from the bottom to the top
+func (s *SyncStack) Values() []interface{} {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.stack.Values()
+}
@@ -0,0 +1,107 @@
+package stack
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSyncStack(t *testing.T) {
+	s := NewCappedSyncStack(100)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				if err := s.TryPush(i*10 + j); err != nil {
+					t.Error("SyncStack TryPush error")
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if !s.IsFull() || s.Len() != 100 || s.Capacity() != 100 {
+		t.Errorf("Got %v expected %v for stack size", s.Len(), 100)
+	}
+	if err := s.TryPush(0); err != CapacityFullErr {
+		t.Errorf("Got %v expected %v for push to full stack", err, CapacityFullErr)
+	}
+
+	seen := make([]bool, 100)
+	var mu sync.Mutex
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				v := s.Pop().(int)
+				mu.Lock()
+				seen[v] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, ok := range seen {
+		if !ok {
+			t.Errorf("Item %d is lost", i)
+		}
+	}
+	if !s.IsEmpty() || s.Pop() != nil || s.Peek() != nil {
+		t.Error("SyncStack should be empty")
+	}
+
+	s.Push(1)
+	s.Push(2)
+	if values := s.Values(); len(values) != 2 || values[1] != 2 {
+		t.Errorf("Got %v expected %v for values", values, []int{1, 2})
+	}
+	s.Clear()
+	if s.Len() != 0 {
+		t.Error("SyncStack Clear error")
+	}
+}
+
+func TestSyncStackPopWait(t *testing.T) {
+	s := NewSyncStack(0)
+	s.Push(1)
+	if v, err := s.PopWait(context.Background()); err != nil || v != 1 {
+		t.Errorf("Got %v, %v expected %v, %v for pop from non empty stack", v, err, 1, nil)
+	}
+
+	result := make(chan interface{})
+	started := make(chan struct{})
+	for i := 0; i < 3; i++ {
+		go func() {
+			started <- struct{}{}
+			v, err := s.PopWait(context.Background())
+			if err != nil {
+				t.Error("SyncStack PopWait error")
+			}
+			result <- v
+		}()
+	}
+
+	for i := 0; i < 3; i++ {
+		<-started
+	}
+	sum := 0
+	for i := 1; i <= 3; i++ {
+		s.Push(i)
+		sum += (<-result).(int)
+	}
+	if sum != 6 {
+		t.Errorf("Got %v expected %v for sum of popped items", sum, 6)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if v, err := s.PopWait(ctx); err != context.DeadlineExceeded || v != nil {
+		t.Errorf("Got %v, %v expected %v, %v for pop from empty stack", v, err, nil, context.DeadlineExceeded)
+	}
+}
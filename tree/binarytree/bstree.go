@@ -200,6 +200,41 @@ func (tree *BSTree) InsertNonRecursive(o base.Comparable) (node *BSTree) {
 	}
 }
 
+// InsertChecked insert a value in strict mode and return a inserted tree,
+// a base.IncomparableError is returned if the value is not comparable with
+// any value on its search path, which are all the values it is compared
+// with. Values of other types off the path, inserted by Insert, are not
+// detected.
+func (tree *BSTree) InsertChecked(o base.Comparable) (node *BSTree, err error) {
+	if tree == nil && o == nil {
+		return tree, &base.IncomparableError{}
+	}
+	if err = checkSearchPath((*Btree)(tree), o); err != nil {
+		return tree, err
+	}
+
+	return tree.InsertNonRecursive(o), nil
+}
+
+// checkSearchPath checks o is comparable with every element on its search
+// path from node in strict mode.
+func checkSearchPath(node *Btree, o base.Comparable) error {
+	for node != nil {
+		result, err := base.Compare(o, node.Element)
+		if err != nil {
+			return err
+		}
+		if result < 0 {
+			node = node.Left
+		} else if result > 0 {
+			node = node.Right
+		} else {
+			return nil
+		}
+	}
+	return nil
+}
+
 // VerticalPretty print the tree in vertical format.
 func (tree *BSTree) VerticalPretty() *bytes.Buffer {
 	return (*Btree)(tree).VerticalPretty()
@@ -1,6 +1,7 @@
 package binarytree
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -216,3 +217,52 @@ func TestBSTreeInsertNonRecursive(t *testing.T) {
 		t.Error("Btree InsertNonRecursive wrok error")
 	}
 }
+
+func TestBSTreeTupleKey(t *testing.T) {
+	var bstree *BSTree
+
+	bstree = bstree.Insert(base.NewTuple(base.String("b"), base.Int(1)))
+	bstree = bstree.Insert(base.NewTuple(base.String("a"), base.Int(2)))
+	bstree = bstree.Insert(base.NewTuple(base.String("a"), base.Int(1)))
+
+	key := base.NewTuple(base.String("a"), base.Int(2))
+	if node := bstree.Find(key); node == nil || key.CompareTo(node.Element) != 0 {
+		t.Error("BSTree Find tuple key work error")
+	}
+
+	if min := bstree.FindMin(); min.Element.String() != "(a, 1)" {
+		t.Errorf("BSTree FindMin tuple key work error, got %s", min.Element)
+	}
+}
+
+func TestBSTreeInsertChecked(t *testing.T) {
+	var bstree *BSTree
+	var err error
+
+	if bstree, err = bstree.InsertChecked(base.Int(1)); err != nil {
+		t.Errorf("BSTree InsertChecked error %s", err)
+	}
+	if bstree, err = bstree.InsertChecked(base.Int(2)); err != nil {
+		t.Errorf("BSTree InsertChecked error %s", err)
+	}
+
+	if _, err = bstree.InsertChecked(base.Rune('c')); !errors.Is(err, base.ErrIncomparable) {
+		t.Errorf("BSTree InsertChecked heterogeneous key not return ErrIncomparable, got %v", err)
+	}
+
+	if len((*Btree)(bstree).InOrder()) != 2 {
+		t.Error("BSTree InsertChecked inserted a heterogeneous key")
+	}
+
+	// a heterogeneous key below the root, inserted by Insert
+	bstree.InsertNonRecursive(base.Rune('c'))
+	if _, err = bstree.InsertChecked(base.Rune('d')); !errors.Is(err, base.ErrIncomparable) {
+		t.Errorf("BSTree InsertChecked heterogeneous path not return ErrIncomparable, got %v", err)
+	}
+	if _, err = bstree.InsertChecked(base.Int(3)); !errors.Is(err, base.ErrIncomparable) {
+		t.Errorf("BSTree InsertChecked mixed key path not return ErrIncomparable, got %v", err)
+	}
+	if len((*Btree)(bstree).InOrder()) != 3 {
+		t.Error("BSTree InsertChecked inserted a key into a mixed key path")
+	}
+}
@@ -94,6 +94,32 @@ func TestNewBtreeWithPostInOrder(t *testing.T) {
 	}
 }
 
+func TestNewBtreeWithBytes(t *testing.T) {
+	preOrder := base.NewBytesComparableSlice([][]byte{[]byte("b"), []byte("a"), []byte("c")})
+	inOrder := base.NewBytesComparableSlice([][]byte{[]byte("a"), []byte("b"), []byte("c")})
+	postOrder := base.NewBytesComparableSlice([][]byte{[]byte("a"), []byte("c"), []byte("b")})
+
+	btree, err := NewBtreeWithInPreOrder(inOrder, preOrder)
+	if err != nil {
+		t.Errorf("build btree failed %s", err)
+	}
+	for i, v := range btree.PostOrder() {
+		if postOrder[i].CompareTo(v.Element) != 0 {
+			t.Errorf("Got %s expected %s for POST-Order", v.Element, postOrder[i])
+		}
+	}
+
+	btree, err = NewBtreeWithInPostOrder(inOrder, postOrder)
+	if err != nil {
+		t.Errorf("build btree failed %s", err)
+	}
+	for i, v := range btree.PreOrder() {
+		if preOrder[i].CompareTo(v.Element) != 0 {
+			t.Errorf("Got %s expected %s for PRE-Order", v.Element, preOrder[i])
+		}
+	}
+}
+
 func TestBtreePreOrderNonRecursive(t *testing.T) {
 	preOrder := base.NewIntComparableSlice([]int{7, 10, 4, 3, 1, 2, 8, 11})
 	inOrder := base.NewIntComparableSlice([]int{4, 10, 3, 1, 7, 11, 8, 2})
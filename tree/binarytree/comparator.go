@@ -0,0 +1,91 @@
+package binarytree
+
+import (
+	"bytes"
+
+	"github.com/aiden0z/kit/base"
+)
+
+// ComparatorBSTree present a binary search tree ordered by a comparator, so
+// that plain values can be used as elements. Every element is stored as a
+// *base.ComparatorKey holding the plain value.
+type ComparatorBSTree struct {
+	Root       *BSTree
+	comparator base.Comparator
+	size       int
+}
+
+// NewBSTreeWithComparator return an empty binary search tree ordered by the comparator.
+func NewBSTreeWithComparator(comparator base.Comparator) *ComparatorBSTree {
+	return &ComparatorBSTree{comparator: comparator}
+}
+
+// valueOf return the plain value of the node.
+func valueOf(node *BSTree) interface{} {
+	return node.Element.(*base.ComparatorKey).Value
+}
+
+// Size returns the number of values in the tree.
+func (tree *ComparatorBSTree) Size() int {
+	return tree.size
+}
+
+// Insert a value and return the tree.
+func (tree *ComparatorBSTree) Insert(v interface{}) *ComparatorBSTree {
+	if tree.Root.FindNonRecursive(tree.comparator.Key(v)) == nil {
+		tree.Root = tree.Root.InsertNonRecursive(tree.comparator.Key(v))
+		tree.size++
+	}
+	return tree
+}
+
+// Find the node of the value, the plain value of the node is
+// node.Element.(*base.ComparatorKey).Value.
+func (tree *ComparatorBSTree) Find(v interface{}) (node *BSTree) {
+	return tree.Root.FindNonRecursive(tree.comparator.Key(v))
+}
+
+// Get return the stored value equals to v under the comparator.
+func (tree *ComparatorBSTree) Get(v interface{}) (value interface{}, found bool) {
+	node := tree.Find(v)
+	if node == nil {
+		return nil, false
+	}
+	return valueOf(node), true
+}
+
+// FindMin return minimum value
+func (tree *ComparatorBSTree) FindMin() (value interface{}, found bool) {
+	node := tree.Root.FindMinNonRecursive()
+	if node == nil {
+		return nil, false
+	}
+	return valueOf(node), true
+}
+
+// FindMax return maximum value
+func (tree *ComparatorBSTree) FindMax() (value interface{}, found bool) {
+	node := tree.Root.FindMaxNonRecursive()
+	if node == nil {
+		return nil, false
+	}
+	return valueOf(node), true
+}
+
+// Values return all values in IN order.
+func (tree *ComparatorBSTree) Values() (values []interface{}) {
+	for _, node := range (*Btree)(tree.Root).InOrderNonRecursive() {
+		values = append(values, valueOf((*BSTree)(node)))
+	}
+	return
+}
+
+// VerticalPretty print the tree in vertical format.
+func (tree *ComparatorBSTree) VerticalPretty() *bytes.Buffer {
+	return tree.Root.VerticalPretty()
+}
+
+// HorizontalPretty print the tree in horizontal format.
+func (tree *ComparatorBSTree) HorizontalPretty() *bytes.Buffer {
+	return tree.Root.HorizontalPretty()
+}
@@ -0,0 +1,55 @@
+package binarytree
+
+import (
+	"testing"
+
+	"github.com/aiden0z/kit/base"
+)
+
+type person struct {
+	name string
+	age  int
+}
+
+func TestComparatorBSTree(t *testing.T) {
+	byName := base.ByComparator(func(v interface{}) interface{} { return v.(person).name }, base.NaturalComparator)
+	byAge := base.ByComparator(func(v interface{}) interface{} { return v.(person).age }, base.NaturalComparator)
+
+	people := []person{{"bob", 30}, {"alice", 30}, {"carol", 25}, {"alice", 20}}
+
+	tree := NewBSTreeWithComparator(byName.ThenBy(byAge))
+	for _, p := range people {
+		tree.Insert(p)
+	}
+	tree.Insert(person{"bob", 30})
+
+	expected := []person{{"alice", 20}, {"alice", 30}, {"bob", 30}, {"carol", 25}}
+	values := tree.Values()
+	if len(values) != len(expected) || tree.Size() != len(expected) {
+		t.Errorf("ComparatorBSTree size error, got %v expected %v", values, expected)
+		return
+	}
+	for i, v := range values {
+		if v != expected[i] {
+			t.Errorf("ComparatorBSTree order error, got %v expected %v", values, expected)
+		}
+	}
+
+	if v, found := tree.Get(person{"carol", 25}); !found || v != people[2] {
+		t.Error("ComparatorBSTree Get work error")
+	}
+	if _, found := tree.Get(person{"carol", 26}); found {
+		t.Error("ComparatorBSTree Get find a non exist value")
+	}
+
+	byAgeDesc := NewBSTreeWithComparator(byAge.Reverse())
+	for _, p := range people {
+		byAgeDesc.Insert(p)
+	}
+	if v, _ := byAgeDesc.FindMin(); v.(person).age != 30 {
+		t.Errorf("ComparatorBSTree FindMin work error, got %v", v)
+	}
+	if v, _ := byAgeDesc.FindMax(); v.(person).age != 20 {
+		t.Errorf("ComparatorBSTree FindMax work error, got %v", v)
+	}
+}
@@ -0,0 +1,224 @@
+//        Persistent Binary Search Tree
+//
+// A persistent binary search tree never modifies a node once created. Insert
+// and Delete copy the nodes on the search path and return a new version of the
+// tree, the untouched subtrees are shared with the previous version, so that
+// every version stays valid and costs O(log n) extra nodes only.
+//
+// To keep the tree balanced, the tree is a treap, every node gets a random
+// priority when it is created, which gives an expected O(log n) depth.
+//
+// Every Insert or Delete returns a new version and leaves the receiver as is.
+// The returned *PersistentBSTree is the handle of the version: keep it to read
+// the version later, e.g. one per revision for undo and redo.
+//
+// Nodes are shared among versions, they must not be modified by callers. The
+// Morris traversals modify the tree temporarily, do not use them while other
+// goroutines read any version of the tree.
+
+package binarytree
+
+import (
+	"bytes"
+	"math/rand"
+	"unsafe"
+
+	"github.com/aiden0z/kit/base"
+)
+
+// PersistentBSTree present a version of a persistent binary search tree, the
+// value is the handle of the version.
+type PersistentBSTree struct {
+	// Root is shared with other versions, it must not be modified or set
+	Root *Btree
+	size int
+}
+
+// NewPersistentBSTree return an empty persistent binary search tree.
+func NewPersistentBSTree() *PersistentBSTree {
+	return &PersistentBSTree{}
+}
+
+// persistentNode is a node of PersistentBSTree with its treap priority. The
+// Btree is the first field, so the nodes of the tree are exposed as *Btree
+// and converted back to persistentNode.
+type persistentNode struct {
+	Btree
+	priority uint64
+}
+
+func newPersistentNode(o base.Comparable) *Btree {
+	node := &persistentNode{Btree: Btree{Element: o}, priority: rand.Uint64()}
+	return &node.Btree
+}
+
+func priority(node *Btree) uint64 {
+	return (*persistentNode)(unsafe.Pointer(node)).priority
+}
+
+func cloneNode(node *Btree) *Btree {
+	n := *(*persistentNode)(unsafe.Pointer(node))
+	return &n.Btree
+}
+
+// persistentInsert insert o into the subtree and return the new subtree root,
+// the subtree itself is returned if o already exists.
+func persistentInsert(node *Btree, o base.Comparable) *Btree {
+	if node == nil {
+		return newPersistentNode(o)
+	}
+
+	result := o.CompareTo(node.Element)
+	if result < 0 {
+		left := persistentInsert(node.Left, o)
+		if left == node.Left {
+			return node
+		}
+
+		node = cloneNode(node)
+		node.Left = left
+		if priority(left) > priority(node) {
+			// rotate right, both nodes are new copies
+			node.Left = left.Right
+			left.Right = node
+			return left
+		}
+		return node
+	} else if result > 0 {
+		right := persistentInsert(node.Right, o)
+		if right == node.Right {
+			return node
+		}
+
+		node = cloneNode(node)
+		node.Right = right
+		if priority(right) > priority(node) {
+			// rotate left, both nodes are new copies
+			node.Right = right.Left
+			right.Left = node
+			return right
+		}
+		return node
+	}
+
+	return node
+}
+
+// persistentMerge merge two subtrees, all elements in left are less than elements in right.
+func persistentMerge(left, right *Btree) *Btree {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+
+	if priority(left) > priority(right) {
+		node := cloneNode(left)
+		node.Right = persistentMerge(left.Right, right)
+		return node
+	}
+
+	node := cloneNode(right)
+	node.Left = persistentMerge(left, right.Left)
+	return node
+}
+
+// persistentDelete delete o from the subtree and return the new subtree root,
+// the subtree itself is returned if o not exists.
+func persistentDelete(node *Btree, o base.Comparable) *Btree {
+	if node == nil {
+		return nil
+	}
+
+	result := o.CompareTo(node.Element)
+	if result < 0 {
+		left := persistentDelete(node.Left, o)
+		if left == node.Left {
+			return node
+		}
+		node = cloneNode(node)
+		node.Left = left
+		return node
+	} else if result > 0 {
+		right := persistentDelete(node.Right, o)
+		if right == node.Right {
+			return node
+		}
+		node = cloneNode(node)
+		node.Right = right
+		return node
+	}
+
+	return persistentMerge(node.Left, node.Right)
+}
+
+// Size returns the number of nodes in this version.
+func (tree *PersistentBSTree) Size() int {
+	return tree.size
+}
+
+// Insert a value and return the new version, the tree itself is returned if
+// the value already exists.
+func (tree *PersistentBSTree) Insert(o base.Comparable) *PersistentBSTree {
+	root := persistentInsert(tree.Root, o)
+	if root == tree.Root {
+		return tree
+	}
+	return &PersistentBSTree{Root: root, size: tree.size + 1}
+}
+
+// Delete a value and return the new version, the tree itself is returned if
+// the value not exists.
+func (tree *PersistentBSTree) Delete(o base.Comparable) *PersistentBSTree {
+	root := persistentDelete(tree.Root, o)
+	if root == tree.Root {
+		return tree
+	}
+	return &PersistentBSTree{Root: root, size: tree.size - 1}
+}
+
+// Find the specified node
+func (tree *PersistentBSTree) Find(o base.Comparable) *Btree {
+	return (*Btree)((*BSTree)(tree.Root).FindNonRecursive(o))
+}
+
+// FindMin return minimum node
+func (tree *PersistentBSTree) FindMin() *Btree {
+	return (*Btree)((*BSTree)(tree.Root).FindMinNonRecursive())
+}
+
+// FindMax return maximum node
+func (tree *PersistentBSTree) FindMax() *Btree {
+	return (*Btree)((*BSTree)(tree.Root).FindMaxNonRecursive())
+}
+
+// PreOrder return the PRE order traversal of this version
+func (tree *PersistentBSTree) PreOrder() []*Btree {
+	return tree.Root.PreOrderNonRecursive()
+}
+
+// InOrder return the IN order traversal of this version
+func (tree *PersistentBSTree) InOrder() []*Btree {
+	return tree.Root.InOrderNonRecursive()
+}
+
+// PostOrder return the POST order traversal of this version
+func (tree *PersistentBSTree) PostOrder() []*Btree {
+	return tree.Root.PostOrderNonRecursive()
+}
+
+// LevelOrder return the level order traversal of this version
+func (tree *PersistentBSTree) LevelOrder() []*Btree {
+	return tree.Root.LevelOrder()
+}
+
+// VerticalPretty print this version in vertical format.
+func (tree *PersistentBSTree) VerticalPretty() *bytes.Buffer {
+	return tree.Root.VerticalPretty()
+}
+
+// HorizontalPretty print this version in horizontal format.
+func (tree *PersistentBSTree) HorizontalPretty() *bytes.Buffer {
+	return tree.Root.HorizontalPretty()
+}
@@ -0,0 +1,139 @@
+package binarytree
+
+import (
+	"testing"
+
+	"github.com/aiden0z/kit/base"
+)
+
+func assertPersistentBSTreeOrder(t *testing.T, tree *PersistentBSTree, expected []int) {
+	order := tree.InOrder()
+	if len(order) != len(expected) || tree.Size() != len(expected) {
+		t.Errorf("PersistentBSTree size error, got %d expected %d", len(order), len(expected))
+		return
+	}
+	for i, v := range order {
+		if v.Element.CompareTo(base.Int(expected[i])) != 0 {
+			t.Errorf("PersistentBSTree IN-Order error, got %s expected %d", v.Element, expected[i])
+		}
+	}
+}
+
+func TestPersistentBSTreeInsert(t *testing.T) {
+	v0 := NewPersistentBSTree()
+	v1 := v0.Insert(base.Int(6))
+	v2 := v1.Insert(base.Int(3)).Insert(base.Int(9))
+	v3 := v2.Insert(base.Int(5))
+
+	assertPersistentBSTreeOrder(t, v0, nil)
+	assertPersistentBSTreeOrder(t, v1, []int{6})
+	assertPersistentBSTreeOrder(t, v2, []int{3, 6, 9})
+	assertPersistentBSTreeOrder(t, v3, []int{3, 5, 6, 9})
+
+	if v3.Insert(base.Int(5)) != v3 {
+		t.Error("PersistentBSTree Insert an exist value create a new version")
+	}
+
+	if node := v3.Find(base.Int(5)); node == nil || v2.Find(base.Int(5)) != nil {
+		t.Error("PersistentBSTree Find work error")
+	}
+
+	if v3.FindMin().Element.CompareTo(base.Int(3)) != 0 || v3.FindMax().Element.CompareTo(base.Int(9)) != 0 {
+		t.Error("PersistentBSTree FindMin/FindMax work error")
+	}
+}
+
+func TestPersistentBSTreeDelete(t *testing.T) {
+	tree := NewPersistentBSTree()
+	for _, v := range []int{6, 3, 9, 2, 5, 8, 10, 1, 11} {
+		tree = tree.Insert(base.Int(v))
+	}
+
+	deleted := tree.Delete(base.Int(6))
+	assertPersistentBSTreeOrder(t, tree, []int{1, 2, 3, 5, 6, 8, 9, 10, 11})
+	assertPersistentBSTreeOrder(t, deleted, []int{1, 2, 3, 5, 8, 9, 10, 11})
+
+	if deleted.Delete(base.Int(6)) != deleted {
+		t.Error("PersistentBSTree Delete a non exist value create a new version")
+	}
+
+	for _, v := range []int{1, 2, 3, 5, 8, 9, 10, 11} {
+		deleted = deleted.Delete(base.Int(v))
+	}
+	if deleted.Root != nil || deleted.Size() != 0 {
+		t.Error("PersistentBSTree Delete all nodes error")
+	}
+	assertPersistentBSTreeOrder(t, tree, []int{1, 2, 3, 5, 6, 8, 9, 10, 11})
+}
+
+func TestPersistentBSTreeVersions(t *testing.T) {
+	// keep a version handle per revision
+	versions := []*PersistentBSTree{NewPersistentBSTree()}
+	for _, v := range []int{5, 3, 8, 1} {
+		versions = append(versions, versions[len(versions)-1].Insert(base.Int(v)))
+	}
+	latest := versions[len(versions)-1].Delete(base.Int(5)).Delete(base.Int(8))
+	latest = latest.Insert(base.Int(7))
+
+	assertPersistentBSTreeOrder(t, versions[0], nil)
+	assertPersistentBSTreeOrder(t, versions[2], []int{3, 5})
+	assertPersistentBSTreeOrder(t, versions[4], []int{1, 3, 5, 8})
+	assertPersistentBSTreeOrder(t, latest, []int{1, 3, 7})
+	if versions[2].Find(base.Int(5)) == nil || versions[2].Find(base.Int(7)) != nil {
+		t.Error("PersistentBSTree old version changed by later updates")
+	}
+}
+
+func TestPersistentBSTreeSharing(t *testing.T) {
+	tree := NewPersistentBSTree()
+	for i := 0; i < 1000; i++ {
+		tree = tree.Insert(base.Int(i))
+	}
+
+	// sequential insertion still gives a logarithmic depth
+	if depth := tree.Root.Depth(); depth > 40 {
+		t.Errorf("PersistentBSTree unbalanced, depth %d", depth)
+	}
+
+	next := tree.Insert(base.Int(1000))
+
+	shared := make(map[*Btree]bool)
+	for _, node := range tree.PreOrder() {
+		shared[node] = true
+	}
+
+	copied := 0
+	for _, node := range next.PreOrder() {
+		if !shared[node] {
+			copied++
+		}
+	}
+	if copied > next.Root.Depth()+1 {
+		t.Errorf("PersistentBSTree copied %d nodes for one insertion", copied)
+	}
+}
+
+// sameString is an Int whose String is the same for every value.
+type sameString struct {
+	base.Int
+}
+
+func (s sameString) CompareTo(o base.Comparable) int {
+	return s.Int.CompareTo(o.(sameString).Int)
+}
+
+func (s sameString) String() string {
+	return "key"
+}
+
+func TestPersistentBSTreeSameString(t *testing.T) {
+	tree := NewPersistentBSTree()
+	for i := 0; i < 1000; i++ {
+		tree = tree.Insert(sameString{base.Int(i)})
+	}
+
+	// priorities do not depend on String
+	if depth := tree.Root.Depth(); depth > 40 || tree.Size() != 1000 {
+		t.Errorf("PersistentBSTree unbalanced, depth %d", depth)
+	}
+}
@@ -0,0 +1,237 @@
+//        Splay Tree
+//
+// A splay tree is a self-adjusting binary search tree. Every access moves the
+// accessed node to the root by a sequence of rotations (splaying), so that
+// recently accessed keys are cheap to access again. All operations run in
+// O(log n) amortized time.
+//
+// The splay operation is implemented top-down: the tree is split into a left
+// tree, a middle tree and a right tree while walking down from the root, and
+// re-assembled when the target is reached, so no parent pointers or stack are
+// required.
+
+package binarytree
+
+import (
+	"bytes"
+
+	"github.com/aiden0z/kit/base"
+)
+
+// SplayTree present a splay tree, it exposes the same methods as BSTree, and
+// the nodes are returned as *BSTree. Unlike BSTree, the root changes on every
+// access, so the tree is used through the SplayTree instead of the root node:
+// the node returned by Insert is the current root, not a tree to keep, and
+// `tree = tree.Insert(x)` used with BSTree does not apply.
+type SplayTree struct {
+	Root *Btree
+	size int
+}
+
+// NewSplayTree return an empty splay tree.
+func NewSplayTree() *SplayTree {
+	return &SplayTree{}
+}
+
+// splay moves the node which matches the compare function to the root. The
+// compare function returns a negative integer, zero, or a positive integer
+// as the target is less than, equal to, or greater than the element. If no
+// node matches, the last node on the search path becomes the root.
+func (tree *SplayTree) splay(compare func(element base.Comparable) int) {
+	if tree.Root == nil {
+		return
+	}
+
+	// header.Right is the root of the left tree,
+	// header.Left is the root of the right tree.
+	header := new(Btree)
+	left, right := header, header
+	current := tree.Root
+
+	for {
+		result := compare(current.Element)
+		if result < 0 {
+			if current.Left == nil {
+				break
+			}
+			if compare(current.Left.Element) < 0 {
+				// rotate right
+				node := current.Left
+				current.Left = node.Right
+				node.Right = current
+				current = node
+				if current.Left == nil {
+					break
+				}
+			}
+			// link right
+			right.Left = current
+			right = current
+			current = current.Left
+		} else if result > 0 {
+			if current.Right == nil {
+				break
+			}
+			if compare(current.Right.Element) > 0 {
+				// rotate left
+				node := current.Right
+				current.Right = node.Left
+				node.Left = current
+				current = node
+				if current.Right == nil {
+					break
+				}
+			}
+			// link left
+			left.Right = current
+			left = current
+			current = current.Right
+		} else {
+			break
+		}
+	}
+
+	// assemble
+	left.Right = current.Left
+	right.Left = current.Right
+	current.Left = header.Right
+	current.Right = header.Left
+	tree.Root = current
+}
+
+func (tree *SplayTree) splayKey(o base.Comparable) {
+	tree.splay(func(element base.Comparable) int {
+		return o.CompareTo(element)
+	})
+}
+
+// Size returns the number of nodes in the tree.
+func (tree *SplayTree) Size() int {
+	return tree.size
+}
+
+// Find the specified node and splay it to the root, return nil if not found.
+func (tree *SplayTree) Find(o base.Comparable) (node *BSTree) {
+	tree.splayKey(o)
+
+	if tree.Root != nil && o.CompareTo(tree.Root.Element) == 0 {
+		return (*BSTree)(tree.Root)
+	}
+	return nil
+}
+
+// FindNonRecursive is the same as Find, splaying is not recursive.
+func (tree *SplayTree) FindNonRecursive(o base.Comparable) (node *BSTree) {
+	return tree.Find(o)
+}
+
+// FindMin return minimum node and splay it to the root.
+func (tree *SplayTree) FindMin() (node *BSTree) {
+	tree.splay(func(element base.Comparable) int {
+		return -1
+	})
+	return (*BSTree)(tree.Root)
+}
+
+// FindMinNonRecursive is the same as FindMin.
+func (tree *SplayTree) FindMinNonRecursive() (node *BSTree) {
+	return tree.FindMin()
+}
+
+// FindMax return maximum node and splay it to the root.
+func (tree *SplayTree) FindMax() (node *BSTree) {
+	tree.splay(func(element base.Comparable) int {
+		return 1
+	})
+	return (*BSTree)(tree.Root)
+}
+
+// FindMaxNonRecursive is the same as FindMax.
+func (tree *SplayTree) FindMaxNonRecursive() (node *BSTree) {
+	return tree.FindMax()
+}
+
+// Insert a value and return the root, the inserted node becomes the root.
+// The returned node is valid until the next access of the tree.
+func (tree *SplayTree) Insert(o base.Comparable) (node *BSTree) {
+	if tree.Root == nil {
+		tree.Root = &Btree{Element: o}
+		tree.size++
+		return (*BSTree)(tree.Root)
+	}
+
+	tree.splayKey(o)
+
+	result := o.CompareTo(tree.Root.Element)
+	if result == 0 {
+		return (*BSTree)(tree.Root)
+	}
+
+	inserted := &Btree{Element: o}
+	if result < 0 {
+		inserted.Left = tree.Root.Left
+		inserted.Right = tree.Root
+		tree.Root.Left = nil
+	} else {
+		inserted.Right = tree.Root.Right
+		inserted.Left = tree.Root
+		tree.Root.Right = nil
+	}
+
+	tree.Root = inserted
+	tree.size++
+	return (*BSTree)(tree.Root)
+}
+
+// InsertNonRecursive is the same as Insert.
+func (tree *SplayTree) InsertNonRecursive(o base.Comparable) (node *BSTree) {
+	return tree.Insert(o)
+}
+
+// InsertChecked insert a value in strict mode and return the root, a
+// base.IncomparableError is returned if the value is not comparable with
+// any value on its search path, like BSTree.InsertChecked, the tree is not
+// splayed in that case.
+func (tree *SplayTree) InsertChecked(o base.Comparable) (node *BSTree, err error) {
+	if tree.Root == nil && o == nil {
+		return nil, &base.IncomparableError{}
+	}
+	if err = checkSearchPath(tree.Root, o); err != nil {
+		return (*BSTree)(tree.Root), err
+	}
+
+	return tree.Insert(o), nil
+}
+
+// Delete a value and return the tree.
+func (tree *SplayTree) Delete(o base.Comparable) *SplayTree {
+	tree.splayKey(o)
+
+	if tree.Root == nil || o.CompareTo(tree.Root.Element) != 0 {
+		return tree
+	}
+
+	if tree.Root.Left == nil {
+		tree.Root = tree.Root.Right
+	} else {
+		right := tree.Root.Right
+		tree.Root = tree.Root.Left
+		// o is greater than every element in the left tree, so the maximum
+		// becomes the root and has no right child.
+		tree.splayKey(o)
+		tree.Root.Right = right
+	}
+
+	tree.size--
+	return tree
+}
+
+// VerticalPretty print the tree in vertical format.
+func (tree *SplayTree) VerticalPretty() *bytes.Buffer {
+	return tree.Root.VerticalPretty()
+}
+
+// HorizontalPretty print the tree in horizontal format.
+func (tree *SplayTree) HorizontalPretty() *bytes.Buffer {
+	return tree.Root.HorizontalPretty()
+}
@@ -0,0 +1,237 @@
+package binarytree
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/aiden0z/kit/base"
+)
+
+func assertSplayTreeOrder(t *testing.T, tree *SplayTree, expected []int) {
+	order := tree.Root.InOrder()
+	if len(order) != len(expected) || tree.Size() != len(expected) {
+		t.Errorf("SplayTree size error, got %d expected %d", len(order), len(expected))
+		return
+	}
+	for i, v := range order {
+		if v.Element.CompareTo(base.Int(expected[i])) != 0 {
+			t.Errorf("SplayTree IN-Order error, got %s expected %d", v.Element, expected[i])
+		}
+	}
+}
+
+func TestSplayTreeInsert(t *testing.T) {
+	tree := NewSplayTree()
+
+	for _, v := range []int{6, 3, 9, 2, 5, 8, 10, 1, 11, 5} {
+		node := tree.Insert(base.Int(v))
+		if tree.Root.Element.CompareTo(base.Int(v)) != 0 || (*Btree)(node) != tree.Root {
+			t.Error("SplayTree Insert not splay the inserted node to root")
+		}
+	}
+
+	assertSplayTreeOrder(t, tree, []int{1, 2, 3, 5, 6, 8, 9, 10, 11})
+}
+
+func TestSplayTreeFind(t *testing.T) {
+	tree := NewSplayTree()
+
+	if tree.Find(base.Int(1)) != nil {
+		t.Error("SplayTree Find on empty tree not return nil")
+	}
+
+	for _, v := range []int{6, 3, 9, 2, 5, 8, 10, 1, 11} {
+		tree.Insert(base.Int(v))
+	}
+
+	key := base.Int(5)
+	node := tree.Find(key)
+	if node == nil || key.CompareTo(node.Element) != 0 {
+		t.Error("SplayTree Find work error, not find the correct node")
+	}
+	if (*Btree)(node) != tree.Root {
+		t.Error("SplayTree Find not splay the found node to root")
+	}
+
+	if tree.Find(base.Int(100)) != nil {
+		t.Error("SplayTree Find work error, find a non exist target")
+	}
+
+	assertSplayTreeOrder(t, tree, []int{1, 2, 3, 5, 6, 8, 9, 10, 11})
+}
+
+func TestSplayTreeFindMinMax(t *testing.T) {
+	tree := NewSplayTree()
+
+	if tree.FindMin() != nil || tree.FindMax() != nil {
+		t.Error("SplayTree FindMin/FindMax on empty tree not return nil")
+	}
+
+	for _, v := range []int{6, 3, 9, 2, 5, 8, 10, 1, 11} {
+		tree.Insert(base.Int(v))
+	}
+
+	if min := tree.FindMin(); min.Element.CompareTo(base.Int(1)) != 0 || (*Btree)(min) != tree.Root {
+		t.Error("SplayTree FindMin work error")
+	}
+
+	if max := tree.FindMax(); max.Element.CompareTo(base.Int(11)) != 0 || (*Btree)(max) != tree.Root {
+		t.Error("SplayTree FindMax work error")
+	}
+}
+
+// bstreeMethods is the method set of BSTree which SplayTree provides.
+type bstreeMethods interface {
+	Find(o base.Comparable) *BSTree
+	FindNonRecursive(o base.Comparable) *BSTree
+	FindMin() *BSTree
+	FindMinNonRecursive() *BSTree
+	FindMax() *BSTree
+	FindMaxNonRecursive() *BSTree
+	Insert(o base.Comparable) *BSTree
+	InsertNonRecursive(o base.Comparable) *BSTree
+	InsertChecked(o base.Comparable) (*BSTree, error)
+}
+
+var (
+	_ bstreeMethods = (*BSTree)(nil)
+	_ bstreeMethods = (*SplayTree)(nil)
+)
+
+func TestSplayTreeNonRecursive(t *testing.T) {
+	tree := NewSplayTree()
+	for _, v := range []int{6, 3, 9, 2, 5, 8, 10, 1, 11} {
+		tree.InsertNonRecursive(base.Int(v))
+	}
+	if _, err := tree.InsertChecked(base.Rune('a')); err == nil {
+		t.Error("SplayTree InsertChecked not return error for incomparable value")
+	}
+	if node, err := tree.InsertChecked(base.Int(7)); err != nil || node.Element.CompareTo(base.Int(7)) != 0 {
+		t.Error("SplayTree InsertChecked work error")
+	}
+
+	if node := tree.FindNonRecursive(base.Int(5)); node == nil || node.Element.CompareTo(base.Int(5)) != 0 {
+		t.Error("SplayTree FindNonRecursive work error")
+	}
+	if min := tree.FindMinNonRecursive(); min.Element.CompareTo(base.Int(1)) != 0 {
+		t.Error("SplayTree FindMinNonRecursive work error")
+	}
+	if max := tree.FindMaxNonRecursive(); max.Element.CompareTo(base.Int(11)) != 0 {
+		t.Error("SplayTree FindMaxNonRecursive work error")
+	}
+	assertSplayTreeOrder(t, tree, []int{1, 2, 3, 5, 6, 7, 8, 9, 10, 11})
+}
+
+func TestSplayTreeInsertCheckedMixedKeys(t *testing.T) {
+	tree := NewSplayTree()
+	tree.Insert(base.Int(1))
+	tree.Insert(base.Rune('c'))
+	tree.Insert(base.Int(2))
+
+	// the mixed keys are on the search path, below the root
+	for _, o := range []base.Comparable{base.Int(0), base.Rune('d')} {
+		if _, err := tree.InsertChecked(o); !errors.Is(err, base.ErrIncomparable) {
+			t.Errorf("Got %v expected %v for InsertChecked %v", err, base.ErrIncomparable, o)
+		}
+	}
+	if tree.Size() != 3 {
+		t.Errorf("Got %v expected %v for size", tree.Size(), 3)
+	}
+
+	// the root of the tree changes, the returned node is not the tree
+	root := tree.Insert(base.Int(0))
+	tree.Find(base.Int(2))
+	if (*Btree)(root) == tree.Root {
+		t.Error("SplayTree root not changed by Find")
+	}
+}
+
+func TestSplayTreeDelete(t *testing.T) {
+	tree := NewSplayTree()
+
+	for _, v := range []int{6, 3, 9, 2, 5, 8, 10, 1, 11} {
+		tree.Insert(base.Int(v))
+	}
+
+	tree.Delete(base.Int(100))
+	assertSplayTreeOrder(t, tree, []int{1, 2, 3, 5, 6, 8, 9, 10, 11})
+
+	tree.Delete(base.Int(6))
+	assertSplayTreeOrder(t, tree, []int{1, 2, 3, 5, 8, 9, 10, 11})
+
+	tree.Delete(base.Int(1))
+	tree.Delete(base.Int(11))
+	assertSplayTreeOrder(t, tree, []int{2, 3, 5, 8, 9, 10})
+
+	for _, v := range []int{2, 3, 5, 8, 9, 10} {
+		tree.Delete(base.Int(v))
+	}
+	if tree.Root != nil || tree.Size() != 0 {
+		t.Error("SplayTree Delete all nodes error")
+	}
+}
+
+func TestSplayTreeRandom(t *testing.T) {
+	tree := NewSplayTree()
+	r := rand.New(rand.NewSource(1))
+	set := make(map[int]bool)
+
+	for i := 0; i < 2000; i++ {
+		v := r.Intn(500)
+		if r.Intn(3) == 0 {
+			tree.Delete(base.Int(v))
+			delete(set, v)
+		} else {
+			tree.Insert(base.Int(v))
+			set[v] = true
+		}
+	}
+
+	var expected []int
+	for i := 0; i < 500; i++ {
+		if set[i] {
+			expected = append(expected, i)
+		}
+	}
+	assertSplayTreeOrder(t, tree, expected)
+}
+
+const benchmarkTreeSize = 10000
+
+// zipfKeys return n keys in [0, size) following a Zipfian distribution.
+func zipfKeys(n int) []base.Comparable {
+	r := rand.New(rand.NewSource(1))
+	zipf := rand.NewZipf(r, 1.1, 1, benchmarkTreeSize-1)
+	keys := make([]base.Comparable, n)
+	for i := range keys {
+		keys[i] = base.Int(zipf.Uint64())
+	}
+	return keys
+}
+
+func BenchmarkSplayTreeFindZipf(b *testing.B) {
+	tree := NewSplayTree()
+	for _, v := range rand.New(rand.NewSource(2)).Perm(benchmarkTreeSize) {
+		tree.Insert(base.Int(v))
+	}
+	keys := zipfKeys(b.N)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Find(keys[i])
+	}
+}
+
+func BenchmarkBSTreeFindZipf(b *testing.B) {
+	var tree *BSTree
+	for _, v := range rand.New(rand.NewSource(2)).Perm(benchmarkTreeSize) {
+		tree = tree.InsertNonRecursive(base.Int(v))
+	}
+	keys := zipfKeys(b.N)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.FindNonRecursive(keys[i])
+	}
+}
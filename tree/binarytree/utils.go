@@ -8,10 +8,11 @@ import (
 	"github.com/aiden0z/kit/base"
 )
 
-// indexInSlice  find the k's index in slice
+// indexInSlice  find the k's index in slice, the values are compared by
+// CompareTo, since == panics for the Comparables backed by slices.
 func indexInSlice(k base.Comparable, slice []base.Comparable) int {
 	for i, v := range slice {
-		if v == k {
+		if v.CompareTo(k) == 0 {
 			return i
 		}
 	}
@@ -479,3 +479,20 @@ func (tree *BTree) Remove(key base.Comparable) {
 		tree.size--
 	}
 }
+
+// InsertChecked inserts the key, value entry in strict mode, a
+// base.IncomparableError is returned if the key is not comparable with the
+// keys in tree. All keys in tree share one type if they are inserted by
+// InsertChecked only, so checking against the root is enough.
+func (tree *BTree) InsertChecked(key base.Comparable, value interface{}) error {
+	if tree.Root != nil {
+		if _, err := base.Compare(key, tree.Root.Entries[0].Key); err != nil {
+			return err
+		}
+	} else if key == nil {
+		return &base.IncomparableError{}
+	}
+
+	tree.Insert(key, value)
+	return nil
+}
@@ -1,6 +1,7 @@
 package btree
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/aiden0z/kit/base"
@@ -247,3 +248,83 @@ func TestBTreeRemove5(t *testing.T) {
 	assertValidTreeNode(t, tree.Root.Children[0], 1, 0, []base.Comparable{base.Int(0)}, true)
 	assertValidTreeNode(t, tree.Root.Children[1], 1, 0, []base.Comparable{base.Int(2)}, true)
 }
+
+func TestBTreeTupleKey(t *testing.T) {
+	tree := NewBTree(3)
+	tree.Insert(base.NewTuple(base.String("b"), base.Int(1)), "b1")
+	tree.Insert(base.NewTuple(base.String("a"), base.Int(2)), "a2")
+	tree.Insert(base.NewTuple(base.String("a"), base.Int(1)), "a1")
+	tree.Insert(base.NewTuple(base.String("b"), base.Int(0)), "b0")
+	tree.Insert(base.NewTuple(base.String("a"), base.Int(1)), "a1'")
+
+	assertValidTree(t, tree, 4)
+
+	if value, found := tree.Get(base.NewTuple(base.String("a"), base.Int(1))); !found || value != "a1'" {
+		t.Errorf("Got %v, %v expected %v, %v", value, found, "a1'", true)
+	}
+
+	if key := tree.Left().Entries[0].Key; key.String() != "(a, 1)" {
+		t.Errorf("Got %v expected %v for minimum key", key, "(a, 1)")
+	}
+
+	if key := tree.Right().Entries[len(tree.Right().Entries)-1].Key; key.String() != "(b, 1)" {
+		t.Errorf("Got %v expected %v for maximum key", key, "(b, 1)")
+	}
+}
+
+func TestBTreeInsertChecked(t *testing.T) {
+	tree := NewBTree(3)
+
+	if err := tree.InsertChecked(base.Int(1), "a"); err != nil {
+		t.Errorf("Got %v expected nil", err)
+	}
+	if err := tree.InsertChecked(base.Int(2), "b"); err != nil {
+		t.Errorf("Got %v expected nil", err)
+	}
+
+	err := tree.InsertChecked(base.Rune('c'), "c")
+	if !errors.Is(err, base.ErrIncomparable) {
+		t.Errorf("Got %v expected %v", err, base.ErrIncomparable)
+	}
+
+	assertValidTree(t, tree, 2)
+}
+
+func TestBTreeWithComparator(t *testing.T) {
+	reverse := base.ReverseComparator(base.NaturalComparator)
+	tree := NewBTreeWithComparator(3, reverse)
+	for i, key := range []string{"d", "b", "a", "e", "c", "f"} {
+		tree.Insert(key, i)
+	}
+
+	assertValidTree(t, tree.Tree, 6)
+
+	if value, found := tree.Get("e"); !found || value != 3 {
+		t.Errorf("Got %v, %v expected %v, %v", value, found, 3, true)
+	}
+
+	if key, value, found := tree.Min(); !found || key != "f" || value != 5 {
+		t.Errorf("Got %v, %v expected %v, %v for minimum", key, value, "f", 5)
+	}
+	if key, _, _ := tree.Max(); key != "a" {
+		t.Errorf("Got %v expected %v for maximum key", key, "a")
+	}
+
+	tree.Remove("f")
+	if key, _, _ := tree.Min(); key != "e" || tree.Size() != 5 {
+		t.Errorf("Got %v expected %v for minimum key", key, "e")
+	}
+
+	// keys of the tree are comparable with each other, InsertChecked works
+	if err := tree.Tree.InsertChecked(reverse.Key("g"), 6); err != nil {
+		t.Errorf("Got %v expected nil", err)
+	}
+	if err := tree.Tree.InsertChecked(base.String("h"), 7); !errors.Is(err, base.ErrIncomparable) {
+		t.Errorf("Got %v expected %v", err, base.ErrIncomparable)
+	}
+
+	tree.Clear()
+	if _, _, found := tree.Min(); found || !tree.Empty() {
+		t.Error("Got entries expected empty tree")
+	}
+}
@@ -0,0 +1,79 @@
+package btree
+
+import (
+	"github.com/aiden0z/kit/base"
+)
+
+// ComparatorBTree present a B-tree ordered by a comparator, so that plain
+// values can be used as keys. Every key is stored in Tree as a
+// *base.ComparatorKey holding the plain key.
+type ComparatorBTree struct {
+	Tree       *BTree
+	comparator base.Comparator
+}
+
+// NewBTreeWithComparator return a B-tree ordered by the comparator, order
+// must greate than 2.
+func NewBTreeWithComparator(order int, comparator base.Comparator) *ComparatorBTree {
+	return &ComparatorBTree{Tree: NewBTree(order), comparator: comparator}
+}
+
+// keyOf return the plain key of the entry.
+func keyOf(entry *Entry) interface{} {
+	return entry.Key.(*base.ComparatorKey).Value
+}
+
+// Insert the key, value entry.
+func (tree *ComparatorBTree) Insert(key interface{}, value interface{}) {
+	tree.Tree.Insert(tree.comparator.Key(key), value)
+}
+
+// Get searches the value by key, found is false if key is not in tree.
+func (tree *ComparatorBTree) Get(key interface{}) (value interface{}, found bool) {
+	return tree.Tree.Get(tree.comparator.Key(key))
+}
+
+// Remove remove the entry from the tree by key.
+func (tree *ComparatorBTree) Remove(key interface{}) {
+	tree.Tree.Remove(tree.comparator.Key(key))
+}
+
+// Min return the minimum key and its value.
+func (tree *ComparatorBTree) Min() (key interface{}, value interface{}, found bool) {
+	node := tree.Tree.Left()
+	if node == nil {
+		return nil, nil, false
+	}
+	entry := node.Entries[0]
+	return keyOf(entry), entry.Value, true
+}
+
+// Max return the maximum key and its value.
+func (tree *ComparatorBTree) Max() (key interface{}, value interface{}, found bool) {
+	node := tree.Tree.Right()
+	if node == nil {
+		return nil, nil, false
+	}
+	entry := node.Entries[len(node.Entries)-1]
+	return keyOf(entry), entry.Value, true
+}
+
+// Clear removes all entries from tree.
+func (tree *ComparatorBTree) Clear() {
+	tree.Tree.Clear()
+}
+
+// Empty return true if tree does not contains any entries.
+func (tree *ComparatorBTree) Empty() bool {
+	return tree.Tree.Empty()
+}
+
+// Size returns the number of entries in the tree.
+func (tree *ComparatorBTree) Size() int {
+	return tree.Tree.Size()
+}
+
+// Height returns height of the tree.
+func (tree *ComparatorBTree) Height() int {
+	return tree.Tree.Height()
+}
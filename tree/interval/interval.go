@@ -0,0 +1,304 @@
+// Package interval implements an interval tree.
+// The interval tree is an augmented AVL tree keyed on the low endpoint of
+// intervals (ties broken by the high endpoint), every node also tracks the
+// maximum high endpoint in its subtree, so that subtrees which can not overlap
+// a query are skipped.
+// All intervals are closed, [Low, High].
+// Reference: Introduction to Algorithms, chapter 14.3 Interval trees
+package interval
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aiden0z/kit/base"
+)
+
+// InvalidIntervalErr is returned when the low endpoint is greater than the high endpoint.
+var InvalidIntervalErr = errors.New("invalid interval, low greater than high")
+
+// IntervalTree describe an interval tree.
+type IntervalTree struct {
+	Root *Node
+	size int // Total number of intervals in the tree
+}
+
+// Node describe the tree node.
+type Node struct {
+	Interval *Interval
+	Max      base.Comparable // The maximum high endpoint in the subtree
+	Left     *Node
+	Right    *Node
+	height   int
+}
+
+// Interval describe a closed interval and its associated value.
+type Interval struct {
+	Low   base.Comparable
+	High  base.Comparable
+	Value interface{}
+}
+
+// NewIntervalTree return an empty interval tree.
+func NewIntervalTree() *IntervalTree {
+	return &IntervalTree{}
+}
+
+func (interval *Interval) String() string {
+	return fmt.Sprintf("[%v, %v]", interval.Low, interval.High)
+}
+
+// compareTo compare intervals by low endpoint, then by high endpoint.
+func (interval *Interval) compareTo(low, high base.Comparable) int {
+	if result := interval.Low.CompareTo(low); result != 0 {
+		return result
+	}
+	return interval.High.CompareTo(high)
+}
+
+// overlaps return true if the interval overlaps [low, high].
+func (interval *Interval) overlaps(low, high base.Comparable) bool {
+	return interval.Low.CompareTo(high) <= 0 && low.CompareTo(interval.High) <= 0
+}
+
+func maxComparable(x, y base.Comparable) base.Comparable {
+	if x == nil {
+		return y
+	}
+	if y == nil || x.CompareTo(y) >= 0 {
+		return x
+	}
+	return y
+}
+
+func maxInt(x, y int) int {
+	if x > y {
+		return x
+	}
+	return y
+}
+
+func (node *Node) getHeight() int {
+	if node == nil {
+		return 0
+	}
+	return node.height
+}
+
+func (node *Node) getMax() base.Comparable {
+	if node == nil {
+		return nil
+	}
+	return node.Max
+}
+
+// update recalculate the height and the max endpoint from children.
+func (node *Node) update() {
+	node.height = maxInt(node.Left.getHeight(), node.Right.getHeight()) + 1
+	node.Max = maxComparable(node.Interval.High, maxComparable(node.Left.getMax(), node.Right.getMax()))
+}
+
+func (node *Node) balanceFactor() int {
+	return node.Left.getHeight() - node.Right.getHeight()
+}
+
+func (node *Node) rotateRight() *Node {
+	left := node.Left
+	node.Left = left.Right
+	left.Right = node
+	node.update()
+	left.update()
+	return left
+}
+
+func (node *Node) rotateLeft() *Node {
+	right := node.Right
+	node.Right = right.Left
+	right.Left = node
+	node.update()
+	right.update()
+	return right
+}
+
+// rebalance update the node and restore the AVL property, return the new subtree root.
+func (node *Node) rebalance() *Node {
+	node.update()
+
+	factor := node.balanceFactor()
+	if factor > 1 {
+		if node.Left.balanceFactor() < 0 {
+			node.Left = node.Left.rotateLeft()
+		}
+		return node.rotateRight()
+	}
+	if factor < -1 {
+		if node.Right.balanceFactor() > 0 {
+			node.Right = node.Right.rotateRight()
+		}
+		return node.rotateLeft()
+	}
+	return node
+}
+
+func (node *Node) insert(interval *Interval) (root *Node, inserted bool) {
+	if node == nil {
+		root = &Node{Interval: interval}
+		root.update()
+		return root, true
+	}
+
+	result := interval.compareTo(node.Interval.Low, node.Interval.High)
+	if result < 0 {
+		node.Left, inserted = node.Left.insert(interval)
+	} else if result > 0 {
+		node.Right, inserted = node.Right.insert(interval)
+	} else {
+		// update
+		node.Interval = interval
+		return node, false
+	}
+
+	return node.rebalance(), inserted
+}
+
+// deleteMin remove the minimum node in subtree, return the new subtree root and the removed node.
+func (node *Node) deleteMin() (root *Node, min *Node) {
+	if node.Left == nil {
+		return node.Right, node
+	}
+	node.Left, min = node.Left.deleteMin()
+	return node.rebalance(), min
+}
+
+func (node *Node) delete(low, high base.Comparable) (root *Node, deleted bool) {
+	if node == nil {
+		return nil, false
+	}
+
+	result := node.Interval.compareTo(low, high)
+	if result > 0 {
+		node.Left, deleted = node.Left.delete(low, high)
+	} else if result < 0 {
+		node.Right, deleted = node.Right.delete(low, high)
+	} else {
+		if node.Left == nil {
+			return node.Right, true
+		}
+		if node.Right == nil {
+			return node.Left, true
+		}
+
+		// replace the node with its successor in IN-order
+		var successor *Node
+		node.Right, successor = node.Right.deleteMin()
+		successor.Left = node.Left
+		successor.Right = node.Right
+		return successor.rebalance(), true
+	}
+
+	if !deleted {
+		return node, false
+	}
+	return node.rebalance(), true
+}
+
+// overlapping collect the intervals overlap [low, high] in IN-order.
+func (node *Node) overlapping(low, high base.Comparable, result []*Interval) []*Interval {
+	// no interval in this subtree ends after low
+	if node == nil || node.Max.CompareTo(low) < 0 {
+		return result
+	}
+
+	result = node.Left.overlapping(low, high, result)
+
+	if node.Interval.overlaps(low, high) {
+		result = append(result, node.Interval)
+	}
+
+	// intervals in the right subtree start after node's low endpoint
+	if node.Interval.Low.CompareTo(high) <= 0 {
+		result = node.Right.overlapping(low, high, result)
+	}
+	return result
+}
+
+// Clear removes all intervals from tree.
+func (tree *IntervalTree) Clear() {
+	tree.Root = nil
+	tree.size = 0
+}
+
+// Empty return true if tree does not contains any intervals.
+func (tree *IntervalTree) Empty() bool {
+	return tree.size == 0
+}
+
+// Size returns the number of intervals in the tree.
+func (tree *IntervalTree) Size() int {
+	return tree.size
+}
+
+// Height returns height of the tree.
+func (tree *IntervalTree) Height() int {
+	return tree.Root.getHeight()
+}
+
+// Insert the interval [low, high] with value, the value is updated if the
+// interval already exists.
+func (tree *IntervalTree) Insert(low, high base.Comparable, value interface{}) error {
+	if low.CompareTo(high) > 0 {
+		return InvalidIntervalErr
+	}
+
+	var inserted bool
+	tree.Root, inserted = tree.Root.insert(&Interval{Low: low, High: high, Value: value})
+	if inserted {
+		tree.size++
+	}
+	return nil
+}
+
+// Delete remove the interval [low, high] from the tree, return true if found.
+func (tree *IntervalTree) Delete(low, high base.Comparable) bool {
+	var deleted bool
+	tree.Root, deleted = tree.Root.delete(low, high)
+	if deleted {
+		tree.size--
+	}
+	return deleted
+}
+
+// Overlapping return all intervals contain the point, ordered by low endpoint.
+func (tree *IntervalTree) Overlapping(point base.Comparable) []*Interval {
+	return tree.Root.overlapping(point, point, nil)
+}
+
+// OverlappingRange return all intervals overlap [low, high], ordered by low endpoint.
+func (tree *IntervalTree) OverlappingRange(low, high base.Comparable) []*Interval {
+	if low.CompareTo(high) > 0 {
+		return nil
+	}
+	return tree.Root.overlapping(low, high, nil)
+}
+
+// AnyOverlap return an interval overlaps [low, high] in O(log n) time complexity,
+// or nil if no interval overlaps.
+func (tree *IntervalTree) AnyOverlap(low, high base.Comparable) *Interval {
+	if low.CompareTo(high) > 0 {
+		return nil
+	}
+
+	node := tree.Root
+	for node != nil && !node.Interval.overlaps(low, high) {
+		if node.Left != nil && node.Left.Max.CompareTo(low) >= 0 {
+			node = node.Left
+		} else {
+			node = node.Right
+		}
+	}
+
+	if node == nil {
+		return nil
+	}
+	return node.Interval
+}
@@ -0,0 +1,188 @@
+package interval
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/aiden0z/kit/base"
+)
+
+// assertValidNode check the AVL property and the max endpoint of every node,
+// return the height of the subtree.
+func assertValidNode(t *testing.T, node *Node) int {
+	if node == nil {
+		return 0
+	}
+
+	left := assertValidNode(t, node.Left)
+	right := assertValidNode(t, node.Right)
+
+	if left-right > 1 || right-left > 1 {
+		t.Errorf("Node %v unbalanced, left height %d right height %d", node.Interval, left, right)
+	}
+
+	max := node.Interval.High
+	if node.Left != nil && node.Left.Max.CompareTo(max) > 0 {
+		max = node.Left.Max
+	}
+	if node.Right != nil && node.Right.Max.CompareTo(max) > 0 {
+		max = node.Right.Max
+	}
+	if node.Max.CompareTo(max) != 0 {
+		t.Errorf("Got %v expected %v for node max", node.Max, max)
+	}
+
+	return maxInt(left, right) + 1
+}
+
+func assertIntervals(t *testing.T, actual []*Interval, expected [][2]int) {
+	if len(actual) != len(expected) {
+		t.Errorf("Got %v expected %v for intervals", actual, expected)
+		return
+	}
+	for i, interval := range actual {
+		if interval.compareTo(base.Int(expected[i][0]), base.Int(expected[i][1])) != 0 {
+			t.Errorf("Got %v expected %v for interval", interval, expected[i])
+		}
+	}
+}
+
+func newTestTree() *IntervalTree {
+	tree := NewIntervalTree()
+	tree.Insert(base.Int(16), base.Int(21), "a")
+	tree.Insert(base.Int(8), base.Int(9), "b")
+	tree.Insert(base.Int(25), base.Int(30), "c")
+	tree.Insert(base.Int(5), base.Int(8), "d")
+	tree.Insert(base.Int(15), base.Int(23), "e")
+	tree.Insert(base.Int(17), base.Int(19), "f")
+	tree.Insert(base.Int(26), base.Int(26), "g")
+	tree.Insert(base.Int(0), base.Int(3), "h")
+	tree.Insert(base.Int(6), base.Int(10), "i")
+	tree.Insert(base.Int(19), base.Int(20), "j")
+	return tree
+}
+
+func TestIntervalTreeInsert(t *testing.T) {
+	tree := newTestTree()
+	assertValidNode(t, tree.Root)
+
+	if tree.Size() != 10 {
+		t.Errorf("Got %v expected %v for tree size", tree.Size(), 10)
+	}
+
+	// update the exist interval
+	tree.Insert(base.Int(16), base.Int(21), "z")
+	if tree.Size() != 10 {
+		t.Errorf("Got %v expected %v for tree size", tree.Size(), 10)
+	}
+	if interval := tree.AnyOverlap(base.Int(21), base.Int(22)); interval == nil || interval.Value != "z" {
+		t.Errorf("Got %v expected %v for updated value", interval, "z")
+	}
+
+	if err := tree.Insert(base.Int(2), base.Int(1), nil); err != InvalidIntervalErr {
+		t.Errorf("Got %v expected %v for invalid interval", err, InvalidIntervalErr)
+	}
+}
+
+func TestIntervalTreeOverlapping(t *testing.T) {
+	tree := newTestTree()
+
+	assertIntervals(t, tree.Overlapping(base.Int(8)), [][2]int{{5, 8}, {6, 10}, {8, 9}})
+	assertIntervals(t, tree.Overlapping(base.Int(20)), [][2]int{{15, 23}, {16, 21}, {19, 20}})
+	assertIntervals(t, tree.Overlapping(base.Int(4)), nil)
+	assertIntervals(t, tree.Overlapping(base.Int(31)), nil)
+}
+
+func TestIntervalTreeOverlappingRange(t *testing.T) {
+	tree := newTestTree()
+
+	assertIntervals(t, tree.OverlappingRange(base.Int(22), base.Int(25)), [][2]int{{15, 23}, {25, 30}})
+	assertIntervals(t, tree.OverlappingRange(base.Int(11), base.Int(14)), nil)
+	assertIntervals(t, tree.OverlappingRange(base.Int(3), base.Int(5)), [][2]int{{0, 3}, {5, 8}})
+	assertIntervals(t, tree.OverlappingRange(base.Int(5), base.Int(3)), nil)
+}
+
+func TestIntervalTreeAnyOverlap(t *testing.T) {
+	tree := newTestTree()
+
+	if interval := tree.AnyOverlap(base.Int(22), base.Int(25)); interval == nil || !interval.overlaps(base.Int(22), base.Int(25)) {
+		t.Errorf("Got %v expected an overlapping interval", interval)
+	}
+
+	if interval := tree.AnyOverlap(base.Int(11), base.Int(14)); interval != nil {
+		t.Errorf("Got %v expected nil", interval)
+	}
+
+	if interval := NewIntervalTree().AnyOverlap(base.Int(1), base.Int(2)); interval != nil {
+		t.Errorf("Got %v expected nil", interval)
+	}
+}
+
+func TestIntervalTreeDelete(t *testing.T) {
+	tree := newTestTree()
+
+	if tree.Delete(base.Int(15), base.Int(24)) {
+		t.Error("Delete a non exist interval return true")
+	}
+
+	if !tree.Delete(base.Int(15), base.Int(23)) {
+		t.Error("Delete an exist interval return false")
+	}
+	assertValidNode(t, tree.Root)
+	assertIntervals(t, tree.Overlapping(base.Int(20)), [][2]int{{16, 21}, {19, 20}})
+
+	if tree.Size() != 9 {
+		t.Errorf("Got %v expected %v for tree size", tree.Size(), 9)
+	}
+
+	tree.Clear()
+	if !tree.Empty() || tree.Root != nil {
+		t.Error("Clear tree error")
+	}
+}
+
+func TestIntervalTreeRandom(t *testing.T) {
+	tree := NewIntervalTree()
+	r := rand.New(rand.NewSource(1))
+	intervals := make(map[[2]int]bool)
+
+	for i := 0; i < 3000; i++ {
+		low := r.Intn(1000)
+		high := low + r.Intn(50)
+		if r.Intn(3) == 0 && len(intervals) > 0 {
+			for key := range intervals {
+				tree.Delete(base.Int(key[0]), base.Int(key[1]))
+				delete(intervals, key)
+				break
+			}
+		} else {
+			tree.Insert(base.Int(low), base.Int(high), nil)
+			intervals[[2]int{low, high}] = true
+		}
+	}
+
+	assertValidNode(t, tree.Root)
+	if tree.Size() != len(intervals) {
+		t.Errorf("Got %v expected %v for tree size", tree.Size(), len(intervals))
+	}
+
+	for i := 0; i < 100; i++ {
+		low := r.Intn(1000)
+		high := low + r.Intn(20)
+
+		count := 0
+		for key := range intervals {
+			if key[0] <= high && low <= key[1] {
+				count++
+			}
+		}
+
+		result := tree.OverlappingRange(base.Int(low), base.Int(high))
+		if len(result) != count {
+			t.Errorf("Got %v expected %v for overlapping count", len(result), count)
+		}
+		if (tree.AnyOverlap(base.Int(low), base.Int(high)) != nil) != (count > 0) {
+			t.Error("AnyOverlap not agree with OverlappingRange")
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package segment
+
+// FenwickTree describe a fenwick tree (binary indexed tree) for prefix sums,
+// it supports point updates and prefix sums in O(log n) time complexity.
+type FenwickTree struct {
+	data []float64 // 1-based, data[i] is the sum of (i - lowbit(i), i]
+}
+
+// NewFenwickTree return a fenwick tree of n zero values.
+func NewFenwickTree(n int) *FenwickTree {
+	return &FenwickTree{data: make([]float64, n+1)}
+}
+
+// NewFenwickTreeFrom return a fenwick tree built from values in O(n) time complexity.
+func NewFenwickTreeFrom(values []float64) *FenwickTree {
+	tree := NewFenwickTree(len(values))
+	copy(tree.data[1:], values)
+
+	for i := 1; i < len(tree.data); i++ {
+		if parent := i + i&-i; parent < len(tree.data) {
+			tree.data[parent] += tree.data[i]
+		}
+	}
+	return tree
+}
+
+// Size returns the number of values in the tree.
+func (tree *FenwickTree) Size() int {
+	return len(tree.data) - 1
+}
+
+// Add delta to the value at index.
+func (tree *FenwickTree) Add(index int, delta float64) error {
+	if index < 0 || index >= tree.Size() {
+		return OutOfRangeErr
+	}
+
+	for i := index + 1; i < len(tree.data); i += i & -i {
+		tree.data[i] += delta
+	}
+	return nil
+}
+
+// Set the value at index.
+func (tree *FenwickTree) Set(index int, value float64) error {
+	current, err := tree.Get(index)
+	if err != nil {
+		return err
+	}
+	return tree.Add(index, value-current)
+}
+
+// Get returns the value at index.
+func (tree *FenwickTree) Get(index int) (float64, error) {
+	if index < 0 || index >= tree.Size() {
+		return 0, OutOfRangeErr
+	}
+	return tree.RangeSum(index, index+1)
+}
+
+// PrefixSum returns the sum of values in [0, end).
+func (tree *FenwickTree) PrefixSum(end int) (float64, error) {
+	if end < 0 || end > tree.Size() {
+		return 0, OutOfRangeErr
+	}
+
+	var sum float64
+	for i := end; i > 0; i -= i & -i {
+		sum += tree.data[i]
+	}
+	return sum, nil
+}
+
+// RangeSum returns the sum of values in [lo, hi).
+func (tree *FenwickTree) RangeSum(lo, hi int) (float64, error) {
+	if lo < 0 || hi > tree.Size() || lo > hi {
+		return 0, InvalidRangeErr
+	}
+
+	left, _ := tree.PrefixSum(lo)
+	right, _ := tree.PrefixSum(hi)
+	return right - left, nil
+}
+
+// RangeFenwickTree describe a fenwick tree supports range updates and range sums
+// in O(log n) time complexity.
+// It keeps two fenwick trees b1 and b2 so that
+// prefixSum(i) = sum(b1, i) * i - sum(b2, i).
+type RangeFenwickTree struct {
+	b1 *FenwickTree
+	b2 *FenwickTree
+}
+
+// NewRangeFenwickTree return a range fenwick tree of n zero values.
+func NewRangeFenwickTree(n int) *RangeFenwickTree {
+	return &RangeFenwickTree{
+		b1: NewFenwickTree(n),
+		b2: NewFenwickTree(n),
+	}
+}
+
+// NewRangeFenwickTreeFrom return a range fenwick tree built from values.
+func NewRangeFenwickTreeFrom(values []float64) *RangeFenwickTree {
+	tree := NewRangeFenwickTree(len(values))
+	for i, v := range values {
+		tree.RangeAdd(i, i+1, v)
+	}
+	return tree
+}
+
+// Size returns the number of values in the tree.
+func (tree *RangeFenwickTree) Size() int {
+	return tree.b1.Size()
+}
+
+// add delta to the suffix [index, n).
+func (tree *RangeFenwickTree) add(index int, delta float64) {
+	if index < tree.Size() {
+		tree.b1.Add(index, delta)
+		tree.b2.Add(index, delta*float64(index))
+	}
+}
+
+// RangeAdd add delta to every value in [lo, hi).
+func (tree *RangeFenwickTree) RangeAdd(lo, hi int, delta float64) error {
+	if lo < 0 || hi > tree.Size() || lo > hi {
+		return InvalidRangeErr
+	}
+
+	tree.add(lo, delta)
+	tree.add(hi, -delta)
+	return nil
+}
+
+// Get returns the value at index.
+func (tree *RangeFenwickTree) Get(index int) (float64, error) {
+	if index < 0 || index >= tree.Size() {
+		return 0, OutOfRangeErr
+	}
+	return tree.RangeSum(index, index+1)
+}
+
+// PrefixSum returns the sum of values in [0, end).
+func (tree *RangeFenwickTree) PrefixSum(end int) (float64, error) {
+	sum1, err := tree.b1.PrefixSum(end)
+	if err != nil {
+		return 0, err
+	}
+	sum2, _ := tree.b2.PrefixSum(end)
+	return sum1*float64(end) - sum2, nil
+}
+
+// RangeSum returns the sum of values in [lo, hi).
+func (tree *RangeFenwickTree) RangeSum(lo, hi int) (float64, error) {
+	if lo < 0 || hi > tree.Size() || lo > hi {
+		return 0, InvalidRangeErr
+	}
+
+	left, _ := tree.PrefixSum(lo)
+	right, _ := tree.PrefixSum(hi)
+	return right - left, nil
+}
@@ -0,0 +1,92 @@
+package segment
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestFenwickTree(t *testing.T) {
+	tree := NewFenwickTreeFrom([]float64{5, 8, 6, 3, 2, 7, 2, 6})
+
+	if tree.Size() != 8 {
+		t.Errorf("Got %v expected %v for size", tree.Size(), 8)
+	}
+	if sum, _ := tree.PrefixSum(8); sum != 39 {
+		t.Errorf("Got %v expected %v for prefix sum", sum, 39)
+	}
+	if sum, _ := tree.RangeSum(2, 5); sum != 11 {
+		t.Errorf("Got %v expected %v for range sum", sum, 11)
+	}
+
+	tree.Add(3, 10)
+	if sum, _ := tree.RangeSum(2, 5); sum != 21 {
+		t.Errorf("Got %v expected %v for range sum", sum, 21)
+	}
+
+	tree.Set(3, 1)
+	if value, _ := tree.Get(3); value != 1 {
+		t.Errorf("Got %v expected %v for value", value, 1)
+	}
+
+	if err := tree.Add(8, 1); err != OutOfRangeErr {
+		t.Errorf("Got %v expected %v", err, OutOfRangeErr)
+	}
+	if _, err := tree.RangeSum(5, 2); err != InvalidRangeErr {
+		t.Errorf("Got %v expected %v", err, InvalidRangeErr)
+	}
+	if _, err := tree.Get(8); err != OutOfRangeErr {
+		t.Errorf("Got %v expected %v", err, OutOfRangeErr)
+	}
+	if err := tree.Set(-1, 1); err != OutOfRangeErr {
+		t.Errorf("Got %v expected %v", err, OutOfRangeErr)
+	}
+}
+
+func TestRangeFenwickTree(t *testing.T) {
+	tree := NewRangeFenwickTreeFrom([]float64{1, 2, 3, 4, 5, 6, 7, 8})
+
+	tree.RangeAdd(2, 6, 10)
+	if sum, _ := tree.PrefixSum(8); sum != 76 {
+		t.Errorf("Got %v expected %v for prefix sum", sum, 76)
+	}
+	if sum, _ := tree.RangeSum(5, 7); sum != 23 {
+		t.Errorf("Got %v expected %v for range sum", sum, 23)
+	}
+	if value, _ := tree.Get(6); value != 7 {
+		t.Errorf("Got %v expected %v for value", value, 7)
+	}
+
+	if err := tree.RangeAdd(0, 9, 1); err != InvalidRangeErr {
+		t.Errorf("Got %v expected %v", err, InvalidRangeErr)
+	}
+	if _, err := tree.Get(8); err != OutOfRangeErr {
+		t.Errorf("Got %v expected %v", err, OutOfRangeErr)
+	}
+}
+
+func TestRangeFenwickTreeRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	n := 50
+	raw := make([]float64, n)
+	tree := NewRangeFenwickTree(n)
+
+	for i := 0; i < 1000; i++ {
+		lo := r.Intn(n)
+		hi := lo + r.Intn(n-lo+1)
+		delta := float64(r.Intn(21) - 10)
+		tree.RangeAdd(lo, hi, delta)
+		for j := lo; j < hi; j++ {
+			raw[j] += delta
+		}
+
+		lo = r.Intn(n)
+		hi = lo + r.Intn(n-lo+1)
+		var expected float64
+		for _, v := range raw[lo:hi] {
+			expected += v
+		}
+		if sum, _ := tree.RangeSum(lo, hi); sum != expected {
+			t.Errorf("Got %v expected %v for range sum [%d, %d)", sum, expected, lo, hi)
+		}
+	}
+}
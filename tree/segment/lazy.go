@@ -0,0 +1,169 @@
+package segment
+
+// ApplyFunc apply an update to the aggregate of a range with length values.
+// For example, adding delta to every value of a sum aggregate is
+// aggregate + delta*length, while for a min aggregate it is aggregate + delta.
+type ApplyFunc func(aggregate, update interface{}, length int) interface{}
+
+// ComposeFunc compose two updates into one, the older update is applied first.
+type ComposeFunc func(older, newer interface{}) interface{}
+
+// LazySegmentTree describe a segment tree supports range updates by lazy
+// propagation, pending updates are kept on the highest covering nodes and
+// pushed down only when the children are visited.
+type LazySegmentTree struct {
+	n        int
+	data     []interface{}
+	lazy     []interface{} // pending update of children, nil if none
+	identity interface{}
+	combine  CombineFunc
+	apply    ApplyFunc
+	compose  ComposeFunc
+}
+
+// NewLazySegmentTree return a lazy segment tree built from values.
+func NewLazySegmentTree(values []interface{}, identity interface{}, combine CombineFunc,
+	apply ApplyFunc, compose ComposeFunc) *LazySegmentTree {
+
+	n := len(values)
+	tree := &LazySegmentTree{
+		n:        n,
+		data:     make([]interface{}, 4*n),
+		lazy:     make([]interface{}, 4*n),
+		identity: identity,
+		combine:  combine,
+		apply:    apply,
+		compose:  compose,
+	}
+
+	if n > 0 {
+		tree.build(values, 1, 0, n)
+	}
+	return tree
+}
+
+func (tree *LazySegmentTree) build(values []interface{}, node, lo, hi int) {
+	if hi-lo == 1 {
+		tree.data[node] = values[lo]
+		return
+	}
+
+	mid := (lo + hi) / 2
+	tree.build(values, 2*node, lo, mid)
+	tree.build(values, 2*node+1, mid, hi)
+	tree.data[node] = tree.combine(tree.data[2*node], tree.data[2*node+1])
+}
+
+// applyTo apply update to the node covers [lo, hi) and record it for its children.
+func (tree *LazySegmentTree) applyTo(node, lo, hi int, update interface{}) {
+	tree.data[node] = tree.apply(tree.data[node], update, hi-lo)
+	if hi-lo > 1 {
+		if tree.lazy[node] == nil {
+			tree.lazy[node] = update
+		} else {
+			tree.lazy[node] = tree.compose(tree.lazy[node], update)
+		}
+	}
+}
+
+// push propagate the pending update of node to its children.
+func (tree *LazySegmentTree) push(node, lo, hi int) {
+	if tree.lazy[node] == nil {
+		return
+	}
+
+	mid := (lo + hi) / 2
+	tree.applyTo(2*node, lo, mid, tree.lazy[node])
+	tree.applyTo(2*node+1, mid, hi, tree.lazy[node])
+	tree.lazy[node] = nil
+}
+
+func (tree *LazySegmentTree) update(node, lo, hi, qlo, qhi int, update interface{}) {
+	if qhi <= lo || hi <= qlo {
+		return
+	}
+	if qlo <= lo && hi <= qhi {
+		tree.applyTo(node, lo, hi, update)
+		return
+	}
+
+	tree.push(node, lo, hi)
+	mid := (lo + hi) / 2
+	tree.update(2*node, lo, mid, qlo, qhi, update)
+	tree.update(2*node+1, mid, hi, qlo, qhi, update)
+	tree.data[node] = tree.combine(tree.data[2*node], tree.data[2*node+1])
+}
+
+func (tree *LazySegmentTree) set(node, lo, hi, index int, value interface{}) {
+	if hi-lo == 1 {
+		tree.data[node] = value
+		return
+	}
+
+	tree.push(node, lo, hi)
+	mid := (lo + hi) / 2
+	if index < mid {
+		tree.set(2*node, lo, mid, index, value)
+	} else {
+		tree.set(2*node+1, mid, hi, index, value)
+	}
+	tree.data[node] = tree.combine(tree.data[2*node], tree.data[2*node+1])
+}
+
+func (tree *LazySegmentTree) query(node, lo, hi, qlo, qhi int) interface{} {
+	if qhi <= lo || hi <= qlo {
+		return tree.identity
+	}
+	if qlo <= lo && hi <= qhi {
+		return tree.data[node]
+	}
+
+	tree.push(node, lo, hi)
+	mid := (lo + hi) / 2
+	return tree.combine(tree.query(2*node, lo, mid, qlo, qhi), tree.query(2*node+1, mid, hi, qlo, qhi))
+}
+
+// Size returns the number of values in the tree.
+func (tree *LazySegmentTree) Size() int {
+	return tree.n
+}
+
+// Get returns the value at index.
+func (tree *LazySegmentTree) Get(index int) (interface{}, error) {
+	if index < 0 || index >= tree.n {
+		return nil, OutOfRangeErr
+	}
+	return tree.query(1, 0, tree.n, index, index+1), nil
+}
+
+// Update set the value at index in O(log n) time complexity.
+func (tree *LazySegmentTree) Update(index int, value interface{}) error {
+	if index < 0 || index >= tree.n {
+		return OutOfRangeErr
+	}
+	tree.set(1, 0, tree.n, index, value)
+	return nil
+}
+
+// RangeUpdate apply update to every value in [lo, hi) in O(log n) time complexity.
+func (tree *LazySegmentTree) RangeUpdate(lo, hi int, update interface{}) error {
+	if lo < 0 || hi > tree.n || lo > hi {
+		return InvalidRangeErr
+	}
+	if lo < hi {
+		tree.update(1, 0, tree.n, lo, hi, update)
+	}
+	return nil
+}
+
+// Query returns the aggregate of values in [lo, hi) in O(log n) time complexity,
+// the identity is returned for an empty range.
+func (tree *LazySegmentTree) Query(lo, hi int) (interface{}, error) {
+	if lo < 0 || hi > tree.n || lo > hi {
+		return nil, InvalidRangeErr
+	}
+	if lo == hi {
+		return tree.identity, nil
+	}
+	return tree.query(1, 0, tree.n, lo, hi), nil
+}
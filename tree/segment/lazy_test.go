@@ -0,0 +1,104 @@
+package segment
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func applyAddToSum(aggregate, update interface{}, length int) interface{} {
+	return aggregate.(int) + update.(int)*length
+}
+
+func applyAddToMin(aggregate, update interface{}, length int) interface{} {
+	return aggregate.(int) + update.(int)
+}
+
+func composeAdd(older, newer interface{}) interface{} {
+	return older.(int) + newer.(int)
+}
+
+func min(a, b interface{}) interface{} {
+	if a.(int) < b.(int) {
+		return a
+	}
+	return b
+}
+
+func TestLazySegmentTreeRangeUpdate(t *testing.T) {
+	values := []interface{}{1, 2, 3, 4, 5, 6, 7, 8}
+	tree := NewLazySegmentTree(values, 0, sum, applyAddToSum, composeAdd)
+
+	if err := tree.RangeUpdate(2, 6, 10); err != nil {
+		t.Errorf("RangeUpdate error %v", err)
+	}
+
+	tests := [][]int{
+		{0, 8, 76},
+		{0, 2, 3},
+		{2, 6, 58},
+		{5, 7, 23},
+		{4, 4, 0},
+	}
+	for _, test := range tests {
+		if value, err := tree.Query(test[0], test[1]); err != nil || value != test[2] {
+			t.Errorf("Got %v, %v expected %v for query [%d, %d)", value, err, test[2], test[0], test[1])
+		}
+	}
+
+	if value, _ := tree.Get(3); value != 14 {
+		t.Errorf("Got %v expected %v for value", value, 14)
+	}
+
+	tree.Update(3, 0)
+	if value, _ := tree.Query(0, 8); value != 62 {
+		t.Errorf("Got %v expected %v for query", value, 62)
+	}
+
+	if err := tree.RangeUpdate(0, 9, 1); err != InvalidRangeErr {
+		t.Errorf("Got %v expected %v", err, InvalidRangeErr)
+	}
+	if _, err := tree.Get(8); err != OutOfRangeErr {
+		t.Errorf("Got %v expected %v", err, OutOfRangeErr)
+	}
+}
+
+func TestLazySegmentTreeRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	n := 50
+	values := make([]interface{}, n)
+	raw := make([]int, n)
+	for i := range values {
+		raw[i] = r.Intn(100)
+		values[i] = raw[i]
+	}
+
+	sumTree := NewLazySegmentTree(values, 0, sum, applyAddToSum, composeAdd)
+	minTree := NewLazySegmentTree(values, 1<<31, min, applyAddToMin, composeAdd)
+
+	for i := 0; i < 1000; i++ {
+		lo := r.Intn(n)
+		hi := lo + r.Intn(n-lo+1)
+		delta := r.Intn(21) - 10
+		sumTree.RangeUpdate(lo, hi, delta)
+		minTree.RangeUpdate(lo, hi, delta)
+		for j := lo; j < hi; j++ {
+			raw[j] += delta
+		}
+
+		lo = r.Intn(n)
+		hi = lo + 1 + r.Intn(n-lo)
+		expectedSum, expectedMin := 0, raw[lo]
+		for _, v := range raw[lo:hi] {
+			expectedSum += v
+			if v < expectedMin {
+				expectedMin = v
+			}
+		}
+		if value, _ := sumTree.Query(lo, hi); value != expectedSum {
+			t.Errorf("Got %v expected %v for sum [%d, %d)", value, expectedSum, lo, hi)
+		}
+		if value, _ := minTree.Query(lo, hi); value != expectedMin {
+			t.Errorf("Got %v expected %v for min [%d, %d)", value, expectedMin, lo, hi)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Package segment implements segment trees and fenwick trees for range aggregates.
+// A segment tree stores an aggregate of every power of two aligned range of a
+// sequence, with a user supplied associative combine function it answers
+// range queries and applies point updates in O(log n) time complexity.
+// All ranges are half-open, [lo, hi), as slices in go.
+// Reference https://codeforces.com/blog/entry/18051
+package segment
+
+import (
+	"errors"
+
+	"github.com/aiden0z/kit/base"
+)
+
+var (
+	// OutOfRangeErr is returned when the index is out of the tree range.
+	OutOfRangeErr = errors.New("index out of range")
+	// InvalidRangeErr is returned when the query range is invalid.
+	InvalidRangeErr = errors.New("invalid range")
+)
+
+// CombineFunc combine two aggregates into one, it must be associative,
+// combine(combine(a, b), c) == combine(a, combine(b, c)), but need not be commutative.
+type CombineFunc func(a, b interface{}) interface{}
+
+// Min is a CombineFunc return the minimum of two base.Comparable,
+// nil is treated as identity.
+func Min(a, b interface{}) interface{} {
+	if a == nil {
+		return b
+	}
+	if b == nil || a.(base.Comparable).CompareTo(b.(base.Comparable)) <= 0 {
+		return a
+	}
+	return b
+}
+
+// Max is a CombineFunc return the maximum of two base.Comparable,
+// nil is treated as identity.
+func Max(a, b interface{}) interface{} {
+	if a == nil {
+		return b
+	}
+	if b == nil || a.(base.Comparable).CompareTo(b.(base.Comparable)) >= 0 {
+		return a
+	}
+	return b
+}
+
+// SegmentTree describe a segment tree with point updates.
+type SegmentTree struct {
+	n        int
+	data     []interface{} // data[n:] are leaves, data[i] = combine(data[2i], data[2i+1])
+	identity interface{}
+	combine  CombineFunc
+}
+
+// NewSegmentTree return a segment tree built from values in O(n) time complexity.
+// identity must satisfy combine(identity, x) == combine(x, identity) == x.
+func NewSegmentTree(values []interface{}, identity interface{}, combine CombineFunc) *SegmentTree {
+	n := len(values)
+	tree := &SegmentTree{
+		n:        n,
+		data:     make([]interface{}, 2*n),
+		identity: identity,
+		combine:  combine,
+	}
+
+	copy(tree.data[n:], values)
+	for i := n - 1; i > 0; i-- {
+		tree.data[i] = combine(tree.data[2*i], tree.data[2*i+1])
+	}
+	return tree
+}
+
+// Size returns the number of values in the tree.
+func (tree *SegmentTree) Size() int {
+	return tree.n
+}
+
+// Get returns the value at index.
+func (tree *SegmentTree) Get(index int) (interface{}, error) {
+	if index < 0 || index >= tree.n {
+		return nil, OutOfRangeErr
+	}
+	return tree.data[tree.n+index], nil
+}
+
+// Update set the value at index in O(log n) time complexity.
+func (tree *SegmentTree) Update(index int, value interface{}) error {
+	if index < 0 || index >= tree.n {
+		return OutOfRangeErr
+	}
+
+	i := tree.n + index
+	tree.data[i] = value
+	for i > 1 {
+		i /= 2
+		tree.data[i] = tree.combine(tree.data[2*i], tree.data[2*i+1])
+	}
+	return nil
+}
+
+// Query returns the aggregate of values in [lo, hi) in O(log n) time complexity,
+// the identity is returned for an empty range.
+func (tree *SegmentTree) Query(lo, hi int) (interface{}, error) {
+	if lo < 0 || hi > tree.n || lo > hi {
+		return nil, InvalidRangeErr
+	}
+
+	// aggregate the left and right parts separately to keep the order for
+	// non-commutative combine functions
+	left, right := tree.identity, tree.identity
+	for lo, hi = lo+tree.n, hi+tree.n; lo < hi; lo, hi = lo/2, hi/2 {
+		if lo&1 == 1 {
+			left = tree.combine(left, tree.data[lo])
+			lo++
+		}
+		if hi&1 == 1 {
+			hi--
+			right = tree.combine(tree.data[hi], right)
+		}
+	}
+	return tree.combine(left, right), nil
+}
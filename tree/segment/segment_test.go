@@ -0,0 +1,125 @@
+package segment
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/aiden0z/kit/base"
+)
+
+func sum(a, b interface{}) interface{} {
+	return a.(int) + b.(int)
+}
+
+func concat(a, b interface{}) interface{} {
+	return a.(string) + b.(string)
+}
+
+func TestSegmentTreeQuery(t *testing.T) {
+	values := []interface{}{5, 8, 6, 3, 2, 7, 2, 6}
+	tree := NewSegmentTree(values, 0, sum)
+
+	tests := [][]int{
+		{0, 8, 39},
+		{0, 1, 5},
+		{2, 5, 11},
+		{3, 3, 0},
+		{7, 8, 6},
+	}
+
+	for _, test := range tests {
+		if value, err := tree.Query(test[0], test[1]); err != nil || value != test[2] {
+			t.Errorf("Got %v, %v expected %v for query [%d, %d)", value, err, test[2], test[0], test[1])
+		}
+	}
+
+	if _, err := tree.Query(-1, 2); err != InvalidRangeErr {
+		t.Errorf("Got %v expected %v", err, InvalidRangeErr)
+	}
+	if _, err := tree.Query(3, 2); err != InvalidRangeErr {
+		t.Errorf("Got %v expected %v", err, InvalidRangeErr)
+	}
+}
+
+func TestSegmentTreeUpdate(t *testing.T) {
+	values := []interface{}{5, 8, 6, 3, 2, 7, 2}
+	tree := NewSegmentTree(values, 0, sum)
+
+	if err := tree.Update(2, 10); err != nil {
+		t.Errorf("Update error %v", err)
+	}
+	if value, _ := tree.Get(2); value != 10 {
+		t.Errorf("Got %v expected %v for value", value, 10)
+	}
+	if value, _ := tree.Query(1, 4); value != 21 {
+		t.Errorf("Got %v expected %v for query", value, 21)
+	}
+
+	if err := tree.Update(7, 1); err != OutOfRangeErr {
+		t.Errorf("Got %v expected %v", err, OutOfRangeErr)
+	}
+}
+
+func TestSegmentTreeNonCommutative(t *testing.T) {
+	values := []interface{}{"a", "b", "c", "d", "e"}
+	tree := NewSegmentTree(values, "", concat)
+
+	for lo := 0; lo <= len(values); lo++ {
+		for hi := lo; hi <= len(values); hi++ {
+			expected := ""
+			for _, v := range values[lo:hi] {
+				expected += v.(string)
+			}
+			if value, _ := tree.Query(lo, hi); value != expected {
+				t.Errorf("Got %v expected %v for query [%d, %d)", value, expected, lo, hi)
+			}
+		}
+	}
+}
+
+func TestSegmentTreeMinMax(t *testing.T) {
+	ints := base.NewIntComparableSlice([]int{7, 10, 4, 3, 1, 2, 8, 11})
+	values := make([]interface{}, len(ints))
+	for i, v := range ints {
+		values[i] = v
+	}
+
+	minTree := NewSegmentTree(values, nil, Min)
+	maxTree := NewSegmentTree(values, nil, Max)
+
+	if value, _ := minTree.Query(0, 4); value != base.Int(3) {
+		t.Errorf("Got %v expected %v for min", value, 3)
+	}
+	if value, _ := maxTree.Query(2, 7); value != base.Int(8) {
+		t.Errorf("Got %v expected %v for max", value, 8)
+	}
+	if value, _ := maxTree.Query(2, 2); value != nil {
+		t.Errorf("Got %v expected nil for empty range", value)
+	}
+}
+
+func TestSegmentTreeRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	n := 100
+	values := make([]interface{}, n)
+	for i := range values {
+		values[i] = r.Intn(100)
+	}
+	tree := NewSegmentTree(values, 0, sum)
+
+	for i := 0; i < 1000; i++ {
+		index := r.Intn(n)
+		values[index] = r.Intn(100)
+		tree.Update(index, values[index])
+
+		lo := r.Intn(n)
+		hi := lo + r.Intn(n-lo+1)
+		expected := 0
+		for _, v := range values[lo:hi] {
+			expected += v.(int)
+		}
+		if value, _ := tree.Query(lo, hi); value != expected {
+			t.Errorf("Got %v expected %v for query [%d, %d)", value, expected, lo, hi)
+		}
+	}
+}
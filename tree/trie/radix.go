@@ -0,0 +1,342 @@
+// Package trie implements prefix trees.
+// RadixTree is a compressed trie for byte-slice (and string) keys, every edge
+// is labeled with a byte sequence and nodes with a single child are merged
+// into their parent, so that the number of nodes is at most 2n.
+// RuneTrie is an uncompressed trie keyed on unicode runes, designed for
+// autocomplete over user visible text.
+// Reference https://en.wikipedia.org/wiki/Radix_tree
+package trie
+
+import (
+	"bytes"
+	"sort"
+	"unsafe"
+)
+
+// WalkFunc is called for every key visited by walk methods, return false to stop walking.
+type WalkFunc func(key []byte, value interface{}) bool
+
+// RadixTree describe a compressed trie.
+type RadixTree struct {
+	root *radixNode
+	size int // Total number of keys in the tree
+}
+
+type radixNode struct {
+	prefix   []byte // edge label from the parent to the node
+	leaf     bool   // true if a key ends at the node
+	value    interface{}
+	children []*radixNode // sorted by the first byte of prefix
+}
+
+// Stats describe the node count and memory usage estimation of a tree.
+type Stats struct {
+	Keys   int // Number of keys
+	Nodes  int // Number of nodes, including the root
+	Leaves int // Number of nodes which a key ends at
+	Bytes  int // Estimated memory usage in bytes, values are not included
+}
+
+// NewRadixTree return an empty radix tree.
+func NewRadixTree() *RadixTree {
+	return &RadixTree{root: &radixNode{}}
+}
+
+// commonPrefix return the length of the common prefix of a and b.
+func commonPrefix(a, b []byte) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// child returns the index of the child whose prefix starts with c, and
+// whether the child is found.
+func (node *radixNode) child(c byte) (int, bool) {
+	index := sort.Search(len(node.children), func(i int) bool {
+		return node.children[i].prefix[0] >= c
+	})
+	return index, index < len(node.children) && node.children[index].prefix[0] == c
+}
+
+func (node *radixNode) addChild(child *radixNode) {
+	index, _ := node.child(child.prefix[0])
+	node.children = append(node.children, nil)
+	copy(node.children[index+1:], node.children[index:])
+	node.children[index] = child
+}
+
+func (node *radixNode) deleteChild(index int) {
+	copy(node.children[index:], node.children[index+1:])
+	node.children[len(node.children)-1] = nil
+	node.children = node.children[:len(node.children)-1]
+}
+
+// mergeChild merge the only child into the node if the node holds no key.
+func (node *radixNode) mergeChild() {
+	child := node.children[0]
+	node.prefix = append(node.prefix, child.prefix...)
+	node.leaf = child.leaf
+	node.value = child.value
+	node.children = child.children
+}
+
+// walk visits the leaves of the subtree in key order, key is the full key of the node.
+func (node *radixNode) walk(key []byte, fn WalkFunc) bool {
+	if node.leaf && !fn(key, node.value) {
+		return false
+	}
+
+	for _, child := range node.children {
+		if !child.walk(append(key[:len(key):len(key)], child.prefix...), fn) {
+			return false
+		}
+	}
+	return true
+}
+
+func (node *radixNode) stats(stats *Stats) {
+	stats.Nodes++
+	stats.Bytes += int(unsafe.Sizeof(*node)) + cap(node.prefix) + cap(node.children)*int(unsafe.Sizeof(node))
+	if node.leaf {
+		stats.Leaves++
+	}
+	for _, child := range node.children {
+		child.stats(stats)
+	}
+}
+
+// Size returns the number of keys in the tree.
+func (tree *RadixTree) Size() int {
+	return tree.size
+}
+
+// Empty return true if tree does not contains any keys.
+func (tree *RadixTree) Empty() bool {
+	return tree.size == 0
+}
+
+// Clear removes all keys from tree.
+func (tree *RadixTree) Clear() {
+	tree.root = &radixNode{}
+	tree.size = 0
+}
+
+// Insert the key, value entry, return true if the key is new or false if
+// the value of an exist key is updated.
+func (tree *RadixTree) Insert(key []byte, value interface{}) (inserted bool) {
+	node := tree.root
+	search := key
+
+	for {
+		if len(search) == 0 {
+			inserted = !node.leaf
+			node.leaf = true
+			node.value = value
+			if inserted {
+				tree.size++
+			}
+			return
+		}
+
+		index, found := node.child(search[0])
+		if !found {
+			node.addChild(&radixNode{
+				prefix: append([]byte{}, search...),
+				leaf:   true,
+				value:  value,
+			})
+			tree.size++
+			return true
+		}
+
+		child := node.children[index]
+		common := commonPrefix(search, child.prefix)
+		if common == len(child.prefix) {
+			node = child
+			search = search[common:]
+			continue
+		}
+
+		// split the child at the common prefix
+		split := &radixNode{prefix: child.prefix[:common:common]}
+		child.prefix = child.prefix[common:]
+		split.children = []*radixNode{child}
+		node.children[index] = split
+
+		node = split
+		search = search[common:]
+	}
+}
+
+// InsertString is Insert with a string key.
+func (tree *RadixTree) InsertString(key string, value interface{}) bool {
+	return tree.Insert([]byte(key), value)
+}
+
+// Get searches the key in tree and returns its value or nil if key is not found.
+func (tree *RadixTree) Get(key []byte) (value interface{}, found bool) {
+	node := tree.root
+	search := key
+
+	for len(search) > 0 {
+		index, ok := node.child(search[0])
+		if !ok || !bytes.HasPrefix(search, node.children[index].prefix) {
+			return nil, false
+		}
+		node = node.children[index]
+		search = search[len(node.prefix):]
+	}
+
+	if node.leaf {
+		return node.value, true
+	}
+	return nil, false
+}
+
+// GetString is Get with a string key.
+func (tree *RadixTree) GetString(key string) (interface{}, bool) {
+	return tree.Get([]byte(key))
+}
+
+// Delete remove the key from the tree, return true if found.
+func (tree *RadixTree) Delete(key []byte) bool {
+	var parent *radixNode
+	var index int
+	node := tree.root
+	search := key
+
+	for len(search) > 0 {
+		i, ok := node.child(search[0])
+		if !ok || !bytes.HasPrefix(search, node.children[i].prefix) {
+			return false
+		}
+		parent, index = node, i
+		node = node.children[i]
+		search = search[len(node.prefix):]
+	}
+
+	if !node.leaf {
+		return false
+	}
+
+	node.leaf = false
+	node.value = nil
+	tree.size--
+
+	// keep the tree compressed
+	if node != tree.root {
+		switch len(node.children) {
+		case 0:
+			parent.deleteChild(index)
+			if parent != tree.root && !parent.leaf && len(parent.children) == 1 {
+				parent.mergeChild()
+			}
+		case 1:
+			node.mergeChild()
+		}
+	}
+	return true
+}
+
+// DeleteString is Delete with a string key.
+func (tree *RadixTree) DeleteString(key string) bool {
+	return tree.Delete([]byte(key))
+}
+
+// LongestPrefix returns the longest key in tree which is a prefix of key.
+func (tree *RadixTree) LongestPrefix(key []byte) (prefix []byte, value interface{}, found bool) {
+	node := tree.root
+	search := key
+	length := 0
+
+	if node.leaf {
+		prefix, value, found = key[:0], node.value, true
+	}
+
+	for len(search) > 0 {
+		index, ok := node.child(search[0])
+		if !ok || !bytes.HasPrefix(search, node.children[index].prefix) {
+			break
+		}
+		node = node.children[index]
+		search = search[len(node.prefix):]
+		length += len(node.prefix)
+
+		if node.leaf {
+			prefix, value, found = key[:length], node.value, true
+		}
+	}
+	return
+}
+
+// WalkPrefix visits all keys start with prefix in key order.
+func (tree *RadixTree) WalkPrefix(prefix []byte, fn WalkFunc) {
+	node := tree.root
+	search := prefix
+	key := []byte{}
+
+	for len(search) > 0 {
+		index, ok := node.child(search[0])
+		if !ok {
+			return
+		}
+
+		node = node.children[index]
+		key = append(key, node.prefix...)
+		if bytes.HasPrefix(search, node.prefix) {
+			search = search[len(node.prefix):]
+		} else if bytes.HasPrefix(node.prefix, search) {
+			// the prefix ends in the middle of the edge
+			search = nil
+		} else {
+			return
+		}
+	}
+
+	node.walk(key, fn)
+}
+
+// Walk visits all keys in key order.
+func (tree *RadixTree) Walk(fn WalkFunc) {
+	tree.root.walk([]byte{}, fn)
+}
+
+// Keys returns all keys in key order.
+func (tree *RadixTree) Keys() (keys [][]byte) {
+	tree.Walk(func(key []byte, value interface{}) bool {
+		keys = append(keys, append([]byte{}, key...))
+		return true
+	})
+	return
+}
+
+// Minimum returns the smallest key in tree.
+func (tree *RadixTree) Minimum() (key []byte, value interface{}, found bool) {
+	tree.Walk(func(k []byte, v interface{}) bool {
+		key, value, found = append([]byte{}, k...), v, true
+		return false
+	})
+	return
+}
+
+// Maximum returns the largest key in tree.
+func (tree *RadixTree) Maximum() (key []byte, value interface{}, found bool) {
+	node := tree.root
+	for len(node.children) > 0 {
+		node = node.children[len(node.children)-1]
+		key = append(key, node.prefix...)
+	}
+	if node.leaf {
+		return key, node.value, true
+	}
+	return nil, nil, false
+}
+
+// Stats returns the node count and memory statistics of the tree.
+func (tree *RadixTree) Stats() Stats {
+	stats := Stats{Keys: tree.size}
+	tree.root.stats(&stats)
+	return stats
+}
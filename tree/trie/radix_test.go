@@ -0,0 +1,189 @@
+package trie
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+func TestRadixTreeInsertGet(t *testing.T) {
+	tree := NewRadixTree()
+	keys := []string{"romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus", "rom", ""}
+
+	for i, key := range keys {
+		if !tree.InsertString(key, i) {
+			t.Errorf("Insert new key %q return false", key)
+		}
+	}
+	if tree.InsertString("rom", -1) {
+		t.Error("Insert exist key return true")
+	}
+
+	if tree.Size() != len(keys) {
+		t.Errorf("Got %v expected %v for tree size", tree.Size(), len(keys))
+	}
+
+	for i, key := range keys {
+		expected := interface{}(i)
+		if key == "rom" {
+			expected = -1
+		}
+		if value, found := tree.GetString(key); !found || value != expected {
+			t.Errorf("Got %v, %v expected %v for key %q", value, found, expected, key)
+		}
+	}
+
+	for _, key := range []string{"r", "ro", "roma", "rubi", "rubiconx", "x"} {
+		if value, found := tree.GetString(key); found {
+			t.Errorf("Got %v for non exist key %q", value, key)
+		}
+	}
+}
+
+func TestRadixTreeDelete(t *testing.T) {
+	tree := NewRadixTree()
+	keys := []string{"romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus"}
+	for i, key := range keys {
+		tree.InsertString(key, i)
+	}
+
+	if tree.DeleteString("rom") {
+		t.Error("Delete non exist key return true")
+	}
+
+	for i, key := range keys {
+		if !tree.DeleteString(key) {
+			t.Errorf("Delete exist key %q return false", key)
+		}
+		if _, found := tree.GetString(key); found {
+			t.Errorf("Get deleted key %q", key)
+		}
+		for _, other := range keys[i+1:] {
+			if _, found := tree.GetString(other); !found {
+				t.Errorf("Lost key %q after delete %q", other, key)
+			}
+		}
+	}
+
+	if !tree.Empty() {
+		t.Error("Tree not empty after delete all keys")
+	}
+	if stats := tree.Stats(); stats.Nodes != 1 {
+		t.Errorf("Got %v expected %v for nodes after delete all keys", stats.Nodes, 1)
+	}
+}
+
+func TestRadixTreeLongestPrefix(t *testing.T) {
+	tree := NewRadixTree()
+	tree.InsertString("/", "root")
+	tree.InsertString("/api", "api")
+	tree.InsertString("/api/v1/users", "users")
+
+	tests := [][]string{
+		{"/api/v1/users/1", "/api/v1/users", "users"},
+		{"/api/v1/user", "/api", "api"},
+		{"/apix", "/api", "api"},
+		{"/static", "/", "root"},
+	}
+
+	for _, test := range tests {
+		prefix, value, found := tree.LongestPrefix([]byte(test[0]))
+		if !found || string(prefix) != test[1] || value != test[2] {
+			t.Errorf("Got %q, %v expected %q, %v for key %q", prefix, value, test[1], test[2], test[0])
+		}
+	}
+
+	if _, _, found := tree.LongestPrefix([]byte("api")); found {
+		t.Error("LongestPrefix found a non exist prefix")
+	}
+}
+
+func TestRadixTreeWalkPrefix(t *testing.T) {
+	tree := NewRadixTree()
+	for _, key := range []string{"romane", "romanus", "romulus", "rubens", "ruber", "rubicon"} {
+		tree.InsertString(key, nil)
+	}
+
+	tests := map[string][]string{
+		"rom":    {"romane", "romanus", "romulus"},
+		"ro":     {"romane", "romanus", "romulus"},
+		"rube":   {"rubens", "ruber"},
+		"rubens": {"rubens"},
+		"x":      nil,
+		"romx":   nil,
+	}
+
+	for prefix, expected := range tests {
+		var keys []string
+		tree.WalkPrefix([]byte(prefix), func(key []byte, value interface{}) bool {
+			keys = append(keys, string(key))
+			return true
+		})
+		if len(keys) != len(expected) {
+			t.Errorf("Got %v expected %v for prefix %q", keys, expected, prefix)
+			continue
+		}
+		for i := range keys {
+			if keys[i] != expected[i] {
+				t.Errorf("Got %v expected %v for prefix %q", keys, expected, prefix)
+			}
+		}
+	}
+
+	// stop walking
+	count := 0
+	tree.WalkPrefix([]byte("r"), func(key []byte, value interface{}) bool {
+		count++
+		return count < 2
+	})
+	if count != 2 {
+		t.Errorf("Got %v expected %v for walked keys", count, 2)
+	}
+}
+
+func TestRadixTreeOrder(t *testing.T) {
+	tree := NewRadixTree()
+	r := rand.New(rand.NewSource(1))
+	set := make(map[string]bool)
+
+	for i := 0; i < 2000; i++ {
+		key := strconv.Itoa(r.Intn(1000))
+		if r.Intn(3) == 0 {
+			tree.DeleteString(key)
+			delete(set, key)
+		} else {
+			tree.InsertString(key, nil)
+			set[key] = true
+		}
+	}
+
+	var expected []string
+	for key := range set {
+		expected = append(expected, key)
+	}
+	sort.Strings(expected)
+
+	keys := tree.Keys()
+	if len(keys) != len(expected) || tree.Size() != len(expected) {
+		t.Errorf("Got %v expected %v for keys", len(keys), len(expected))
+		return
+	}
+	for i := range keys {
+		if string(keys[i]) != expected[i] {
+			t.Errorf("Got %q expected %q for key", keys[i], expected[i])
+		}
+	}
+
+	if min, _, _ := tree.Minimum(); string(min) != expected[0] {
+		t.Errorf("Got %q expected %q for minimum", min, expected[0])
+	}
+	if max, _, _ := tree.Maximum(); string(max) != expected[len(expected)-1] {
+		t.Errorf("Got %q expected %q for maximum", max, expected[len(expected)-1])
+	}
+
+	stats := tree.Stats()
+	if stats.Keys != len(expected) || stats.Leaves != len(expected) || stats.Nodes > 2*len(expected)+1 {
+		t.Errorf("Got %+v for stats of %d keys", stats, len(expected))
+	}
+}
@@ -0,0 +1,219 @@
+package trie
+
+import (
+	"sort"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// RuneWalkFunc is called for every key visited by RuneTrie walk methods,
+// return false to stop walking.
+type RuneWalkFunc func(key string, value interface{}) bool
+
+// RuneTrie describe a trie keyed on unicode runes.
+type RuneTrie struct {
+	root *runeNode
+	size int // Total number of keys in the trie
+}
+
+type runeNode struct {
+	leaf     bool // true if a key ends at the node
+	value    interface{}
+	children map[rune]*runeNode
+}
+
+// NewRuneTrie return an empty rune trie.
+func NewRuneTrie() *RuneTrie {
+	return &RuneTrie{root: &runeNode{}}
+}
+
+// walk visits the leaves of the subtree in key order, key is the full key of the node.
+func (node *runeNode) walk(key []rune, fn RuneWalkFunc) bool {
+	if node.leaf && !fn(string(key), node.value) {
+		return false
+	}
+
+	runes := make([]rune, 0, len(node.children))
+	for r := range node.children {
+		runes = append(runes, r)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+
+	for _, r := range runes {
+		if !node.children[r].walk(append(key[:len(key):len(key)], r), fn) {
+			return false
+		}
+	}
+	return true
+}
+
+func (node *runeNode) stats(stats *Stats) {
+	stats.Nodes++
+	stats.Bytes += int(unsafe.Sizeof(*node))
+	if node.children != nil {
+		// rough estimation of map buckets, key and value per entry
+		stats.Bytes += len(node.children) * int(unsafe.Sizeof(rune(0))+unsafe.Sizeof(node))
+	}
+	if node.leaf {
+		stats.Leaves++
+	}
+	for _, child := range node.children {
+		child.stats(stats)
+	}
+}
+
+// find returns the node of key or nil if not exist.
+func (trie *RuneTrie) find(key string) *runeNode {
+	node := trie.root
+	for _, r := range key {
+		node = node.children[r]
+		if node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// Size returns the number of keys in the trie.
+func (trie *RuneTrie) Size() int {
+	return trie.size
+}
+
+// Empty return true if trie does not contains any keys.
+func (trie *RuneTrie) Empty() bool {
+	return trie.size == 0
+}
+
+// Clear removes all keys from trie.
+func (trie *RuneTrie) Clear() {
+	trie.root = &runeNode{}
+	trie.size = 0
+}
+
+// Insert the key, value entry, return true if the key is new or false if
+// the value of an exist key is updated.
+func (trie *RuneTrie) Insert(key string, value interface{}) (inserted bool) {
+	node := trie.root
+	for _, r := range key {
+		child := node.children[r]
+		if child == nil {
+			if node.children == nil {
+				node.children = make(map[rune]*runeNode)
+			}
+			child = &runeNode{}
+			node.children[r] = child
+		}
+		node = child
+	}
+
+	inserted = !node.leaf
+	node.leaf = true
+	node.value = value
+	if inserted {
+		trie.size++
+	}
+	return
+}
+
+// Get searches the key in trie and returns its value or nil if key is not found.
+func (trie *RuneTrie) Get(key string) (value interface{}, found bool) {
+	node := trie.find(key)
+	if node == nil || !node.leaf {
+		return nil, false
+	}
+	return node.value, true
+}
+
+// Delete remove the key from the trie, return true if found.
+func (trie *RuneTrie) Delete(key string) bool {
+	runes := []rune(key)
+	path := make([]*runeNode, 0, len(runes)+1)
+
+	node := trie.root
+	path = append(path, node)
+	for _, r := range runes {
+		node = node.children[r]
+		if node == nil {
+			return false
+		}
+		path = append(path, node)
+	}
+
+	if !node.leaf {
+		return false
+	}
+	node.leaf = false
+	node.value = nil
+	trie.size--
+
+	// remove the nodes which hold no key and have no children
+	for i := len(runes); i > 0; i-- {
+		node = path[i]
+		if node.leaf || len(node.children) > 0 {
+			break
+		}
+		delete(path[i-1].children, runes[i-1])
+	}
+	return true
+}
+
+// HasPrefix return true if any key in trie starts with prefix.
+func (trie *RuneTrie) HasPrefix(prefix string) bool {
+	return trie.find(prefix) != nil && (prefix != "" || trie.size > 0)
+}
+
+// LongestPrefix returns the longest key in trie which is a prefix of key.
+func (trie *RuneTrie) LongestPrefix(key string) (prefix string, value interface{}, found bool) {
+	node := trie.root
+	if node.leaf {
+		prefix, value, found = "", node.value, true
+	}
+
+	for end := 0; end < len(key); {
+		r, width := utf8.DecodeRuneInString(key[end:])
+		end += width
+
+		node = node.children[r]
+		if node == nil {
+			break
+		}
+		if node.leaf {
+			prefix, value, found = key[:end], node.value, true
+		}
+	}
+	return
+}
+
+// WalkPrefix visits all keys start with prefix in key order.
+func (trie *RuneTrie) WalkPrefix(prefix string, fn RuneWalkFunc) {
+	node := trie.find(prefix)
+	if node != nil {
+		node.walk([]rune(prefix), fn)
+	}
+}
+
+// Walk visits all keys in key order.
+func (trie *RuneTrie) Walk(fn RuneWalkFunc) {
+	trie.root.walk([]rune{}, fn)
+}
+
+// Complete returns at most limit keys start with prefix in key order,
+// all keys are returned if limit is negative.
+func (trie *RuneTrie) Complete(prefix string, limit int) (keys []string) {
+	if limit == 0 {
+		return
+	}
+
+	trie.WalkPrefix(prefix, func(key string, value interface{}) bool {
+		keys = append(keys, key)
+		return limit < 0 || len(keys) < limit
+	})
+	return
+}
+
+// Stats returns the node count and memory statistics of the trie.
+func (trie *RuneTrie) Stats() Stats {
+	stats := Stats{Keys: trie.size}
+	trie.root.stats(&stats)
+	return stats
+}
@@ -0,0 +1,80 @@
+package trie
+
+import (
+	"testing"
+)
+
+func TestRuneTrie(t *testing.T) {
+	trie := NewRuneTrie()
+	words := []string{"北京", "北京大学", "北海", "南京", "nice", "niño"}
+
+	for i, word := range words {
+		if !trie.Insert(word, i) {
+			t.Errorf("Insert new key %q return false", word)
+		}
+	}
+	if trie.Insert("北海", 10) {
+		t.Error("Insert exist key return true")
+	}
+
+	if value, found := trie.Get("北京"); !found || value != 0 {
+		t.Errorf("Got %v, %v expected %v for key %q", value, found, 0, "北京")
+	}
+	if _, found := trie.Get("北"); found {
+		t.Error("Get a non exist key")
+	}
+
+	if !trie.HasPrefix("北") || trie.HasPrefix("西") {
+		t.Error("HasPrefix work error")
+	}
+
+	if prefix, value, found := trie.LongestPrefix("北京大学生"); !found || prefix != "北京大学" || value != 1 {
+		t.Errorf("Got %q, %v expected %q for longest prefix", prefix, value, "北京大学")
+	}
+	if prefix, _, found := trie.LongestPrefix("北京大"); !found || prefix != "北京" {
+		t.Errorf("Got %q expected %q for longest prefix", prefix, "北京")
+	}
+
+	completes := trie.Complete("北", -1)
+	expected := []string{"北京", "北京大学", "北海"}
+	if len(completes) != len(expected) {
+		t.Errorf("Got %v expected %v for complete", completes, expected)
+	} else {
+		for i := range completes {
+			if completes[i] != expected[i] {
+				t.Errorf("Got %v expected %v for complete", completes, expected)
+			}
+		}
+	}
+
+	if completes := trie.Complete("ni", 1); len(completes) != 1 || completes[0] != "nice" {
+		t.Errorf("Got %v expected %v for complete", completes, []string{"nice"})
+	}
+}
+
+func TestRuneTrieDelete(t *testing.T) {
+	trie := NewRuneTrie()
+	for _, word := range []string{"北京", "北京大学", "北海"} {
+		trie.Insert(word, nil)
+	}
+
+	if trie.Delete("北") {
+		t.Error("Delete non exist key return true")
+	}
+	if !trie.Delete("北京") {
+		t.Error("Delete exist key return false")
+	}
+	if _, found := trie.Get("北京大学"); !found {
+		t.Error("Lost key after delete its prefix")
+	}
+
+	trie.Delete("北京大学")
+	trie.Delete("北海")
+
+	if !trie.Empty() || trie.HasPrefix("北") {
+		t.Error("Trie not empty after delete all keys")
+	}
+	if stats := trie.Stats(); stats.Nodes != 1 {
+		t.Errorf("Got %v expected %v for nodes after delete all keys", stats.Nodes, 1)
+	}
+}